@@ -10,12 +10,14 @@ import (
 
 // ClassExtractor is responsible for identifying classes from the diagram.
 // ClassExtractor chịu trách nhiệm xác định các lớp từ biểu đồ.
-type ClassExtractor struct{}
+type ClassExtractor struct {
+	diagnostics *models.Diagnostics
+}
 
-// NewClassExtractor creates a new instance of ClassExtractor.
-// NewClassExtractor tạo một phiên bản mới của ClassExtractor.
-func NewClassExtractor() *ClassExtractor {
-	return &ClassExtractor{}
+// NewClassExtractor creates a new instance of ClassExtractor that reports misspelled stereotypes to diagnostics.
+// NewClassExtractor tạo một phiên bản mới của ClassExtractor, báo cáo các khuôn mẫu viết sai vào diagnostics.
+func NewClassExtractor(diagnostics *models.Diagnostics) *ClassExtractor {
+	return &ClassExtractor{diagnostics: diagnostics}
 }
 
 // Extract identifies classes (swimlanes) from the list of cells.
@@ -26,7 +28,8 @@ func (ce *ClassExtractor) Extract(cells []models.MxCell) map[string]*models.Clas
 	for _, cell := range cells {
 		// Mô tả: swimlane thường được sử dụng để đại diện cho các lớp trong sơ đồ UML.
 		// Chúng có thể chứa các phần tử khác như trường và phương thức.
-		if strings.Contains(cell.Style, "swimlane") {
+		// Khung gói (swimlane có kiểu "package") không phải là lớp
+		if strings.Contains(cell.Style, "swimlane") && !isPackageContainer(cell) {
 			rawName := utils.CleanHTML(cell.Value)
 			name, classType := ce.parseClassNameAndType(cell.Value) // Pass RAW for abstract detection
 
@@ -34,9 +37,17 @@ func (ce *ClassExtractor) Extract(cells []models.MxCell) map[string]*models.Clas
 				ID:      cell.ID,
 				Name:    name,
 				RawName: rawName,
+				Page:    cell.Page,
 				Type:    classType,
+				// Khuôn mẫu như <<exception>> được giữ lại cho bộ sinh mã
+				Stereotype:     parseStereotype(cell.Value),
+				TypeParameters: parseTypeParameters(cell.Value),
+				// Siêu dữ liệu tùy chỉnh từ phần tử bọc UserObject/object
+				Properties:  cell.Properties,
+				Annotations: parseAnnotations(cell.Properties),
 			}
 			utils.LogVerbose(fmt.Sprintf("Found %s: %s", classType, name))
+			reportStereotypeTypo(ce.diagnostics, cell, cell.Value, name)
 		}
 	}
 	return classes
@@ -55,12 +66,16 @@ func (ce *ClassExtractor) parseClassNameAndType(raw string) (string, models.Clas
 
 	clean := utils.CleanHTML(raw)
 
+	// Generic parameters (Repository<T, ID>) are not part of the name
+	// Tham số kiểu tổng quát (Repository<T, ID>) không thuộc về tên
+	clean, _ = splitGenerics(clean)
+
 	// Robust Stereotype Parsing
 	// Phân tích cú pháp khuôn mẫu (Stereotype) mạnh mẽ
 	// 1. Look for stereotypes like <<Enum>>, «Interface»
 	// 1. Tìm các khuôn mẫu như <<Enum>>, «Interface»
-	reStereo := regexp.MustCompile(`(<<|«)\s*(\w+)\s*(>>|»)`) // (<<|«) bắt đầu, \s* khoảng trắng tùy chọn, (\w+) từ khóa, \s* khoảng trắng tùy chọn, (>>|») kết thúc
-	match := reStereo.FindStringSubmatch(clean)
+	// (<<|«) bắt đầu, ([\w ]+?) từ khóa (có thể nhiều từ: runtime exception), (>>|») kết thúc
+	match := reStereotype.FindStringSubmatch(clean)
 
 	// nếu có (len: << Enum >> sẽ là 3 phần: toàn bộ, <<, Enum, >>)
 	if len(match) > 2 {
@@ -74,7 +89,7 @@ func (ce *ClassExtractor) parseClassNameAndType(raw string) (string, models.Clas
 		}
 		// Remove stereotype from name
 		// Loại bỏ khuôn mẫu khỏi tên
-		clean = reStereo.ReplaceAllString(clean, "")
+		clean = reStereotype.ReplaceAllString(clean, "")
 	} else {
 		// Fallback: Fuzzy keyword detection
 		// Dự phòng: Phát hiện từ khóa mờ
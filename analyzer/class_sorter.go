@@ -0,0 +1,169 @@
+package analyzer
+
+import (
+	"fmt"
+	"nUML/models"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ClassOrder defines the order in which classes are generated and listed in Report.md.
+// ClassOrder định nghĩa thứ tự các lớp được tạo và liệt kê trong Report.md.
+type ClassOrder string
+
+const (
+	// OrderName sorts by package, then name.
+	// OrderName sắp xếp theo gói, rồi theo tên.
+	OrderName ClassOrder = "name"
+
+	// OrderPosition follows the diagram: page order, then top to bottom and left to right
+	// (declaration order for Mermaid and PlantUML).
+	// OrderPosition theo biểu đồ: thứ tự trang, rồi từ trên xuống và trái sang phải
+	// (thứ tự khai báo với Mermaid và PlantUML).
+	OrderPosition ClassOrder = "position"
+
+	// OrderTopological puts every supertype before its subtypes.
+	// OrderTopological đặt mọi kiểu cha trước các kiểu con của nó.
+	OrderTopological ClassOrder = "topological"
+)
+
+// ParseClassOrder converts the value of --order into a ClassOrder.
+// ParseClassOrder chuyển giá trị của --order thành một ClassOrder.
+func ParseClassOrder(value string) (ClassOrder, error) {
+	switch order := ClassOrder(strings.ToLower(value)); order {
+	case OrderName, OrderPosition, OrderTopological:
+		return order, nil
+	}
+	return "", fmt.Errorf("unknown order %q, use name, position or topological (thứ tự không xác định)", value)
+}
+
+// ClassSorter is responsible for turning the class map into a list with a stable order.
+// ClassSorter chịu trách nhiệm chuyển map các lớp thành một danh sách có thứ tự ổn định.
+type ClassSorter struct {
+	order ClassOrder
+}
+
+// NewClassSorter creates a new instance of ClassSorter using the given order.
+// NewClassSorter tạo một phiên bản mới của ClassSorter dùng thứ tự đã cho.
+func NewClassSorter(order ClassOrder) *ClassSorter {
+	return &ClassSorter{order: order}
+}
+
+// Sort returns every class once, in the order of the sorter. Ties are broken by package, name,
+// page and ID so the result is the same on every run.
+// Sort trả về mỗi lớp đúng một lần, theo thứ tự của bộ sắp xếp. Các trường hợp bằng nhau được phân
+// định theo gói, tên, trang và ID nên kết quả giống nhau ở mọi lần chạy.
+func (cs *ClassSorter) Sort(classes map[string]*models.ClassModel) []*models.ClassModel {
+	var list []*models.ClassModel
+	seen := make(map[*models.ClassModel]bool)
+	for _, cls := range classes {
+		if !seen[cls] {
+			seen[cls] = true
+			list = append(list, cls)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return lessByName(list[i], list[j]) })
+
+	switch cs.order {
+	case OrderPosition:
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Position < list[j].Position })
+	case OrderTopological:
+		list = cs.topological(list)
+	}
+	return list
+}
+
+// topological visits the supertypes of each class before the class itself, starting from the
+// name order; edges closing an inheritance cycle are ignored (the resolver reports them).
+// topological duyệt các kiểu cha của mỗi lớp trước chính lớp đó, bắt đầu từ thứ tự theo tên;
+// các cạnh khép kín một vòng kế thừa được bỏ qua (bộ giải quyết đã báo cáo chúng).
+func (cs *ClassSorter) topological(list []*models.ClassModel) []*models.ClassModel {
+	nameToClass := make(map[string]*models.ClassModel)
+	for _, cls := range list {
+		if _, ok := nameToClass[cls.Name]; !ok {
+			nameToClass[cls.Name] = cls
+		}
+	}
+
+	visited := make(map[*models.ClassModel]bool)
+	var order []*models.ClassModel
+	var visit func(cls *models.ClassModel)
+	visit = func(cls *models.ClassModel) {
+		visited[cls] = true
+		for _, rel := range cls.Relationships {
+			if rel.Kind != models.Generalization && rel.Kind != models.Realization {
+				continue
+			}
+			if super, ok := nameToClass[rel.Target]; ok && !visited[super] {
+				visit(super)
+			}
+		}
+		order = append(order, cls)
+	}
+	for _, cls := range list {
+		if !visited[cls] {
+			visit(cls)
+		}
+	}
+	return order
+}
+
+// lessByName orders classes by package, name, page and ID.
+// lessByName sắp xếp các lớp theo gói, tên, trang và ID.
+func lessByName(a, b *models.ClassModel) bool {
+	switch {
+	case a.Package != b.Package:
+		return a.Package < b.Package
+	case a.Name != b.Name:
+		return a.Name < b.Name
+	case a.Page != b.Page:
+		return a.Page < b.Page
+	}
+	return a.ID < b.ID
+}
+
+// assignPositions numbers the classes of one page in reading order (top to bottom, then left to right,
+// using absolute coordinates so classes inside package frames take the frame position into account),
+// starting at offset. It returns the offset for the next page.
+// assignPositions đánh số các lớp của một trang theo thứ tự đọc (từ trên xuống, rồi trái sang phải,
+// dùng tọa độ tuyệt đối để các lớp trong khung gói tính cả vị trí của khung), bắt đầu từ offset.
+// Nó trả về offset cho trang tiếp theo.
+func assignPositions(cells []models.MxCell, classes map[string]*models.ClassModel, offset int) int {
+	byID := make(map[string]models.MxCell)
+	for _, cell := range cells {
+		byID[cell.ID] = cell
+	}
+
+	type placed struct {
+		cls  *models.ClassModel
+		x, y float64
+	}
+	var list []placed
+	for id, cls := range classes {
+		p := placed{cls: cls}
+		// Tọa độ của ô con tương đối với ô cha: cộng dồn dọc chuỗi cha
+		visited := make(map[string]bool)
+		for cell, ok := byID[id]; ok && !visited[cell.ID]; cell, ok = byID[cell.Parent] {
+			visited[cell.ID] = true
+			x, _ := strconv.ParseFloat(cell.Geometry.X, 64)
+			y, _ := strconv.ParseFloat(cell.Geometry.Y, 64)
+			p.x, p.y = p.x+x, p.y+y
+		}
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		switch {
+		case list[i].y != list[j].y:
+			return list[i].y < list[j].y
+		case list[i].x != list[j].x:
+			return list[i].x < list[j].x
+		}
+		return list[i].cls.ID < list[j].cls.ID
+	})
+
+	for i, p := range list {
+		p.cls.Position = offset + i
+	}
+	return offset + len(list)
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"testing"
+)
+
+// sorterFixture builds the classes used by the sorter tests: Circle extends Shape, Shape implements
+// Drawable, and the map holds Circle under two keys (ID and name) like the analyzer does.
+// sorterFixture tạo các lớp dùng trong kiểm thử bộ sắp xếp: Circle kế thừa Shape, Shape triển khai
+// Drawable, và map chứa Circle dưới hai khóa (ID và tên) như bộ phân tích.
+func sorterFixture() map[string]*models.ClassModel {
+	circle := &models.ClassModel{ID: "c1", Name: "Circle", Package: "shapes", Position: 0,
+		Relationships: []models.Relationship{{Kind: models.Generalization, Source: "Circle", Target: "Shape"}}}
+	shape := &models.ClassModel{ID: "c2", Name: "Shape", Package: "shapes", Position: 2,
+		Relationships: []models.Relationship{{Kind: models.Realization, Source: "Shape", Target: "Drawable"}}}
+	drawable := &models.ClassModel{ID: "c3", Name: "Drawable", Package: "shapes", Position: 1}
+	app := &models.ClassModel{ID: "c4", Name: "App", Package: "", Position: 3}
+	return map[string]*models.ClassModel{"c1": circle, "Circle": circle, "c2": shape, "c3": drawable, "c4": app}
+}
+
+func TestClassSorterSort(t *testing.T) {
+	tests := []struct {
+		order ClassOrder
+		want  []string
+	}{
+		{order: OrderName, want: []string{"App", "Circle", "Drawable", "Shape"}},
+		{order: OrderPosition, want: []string{"Circle", "Drawable", "Shape", "App"}},
+		{order: OrderTopological, want: []string{"App", "Drawable", "Shape", "Circle"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.order), func(t *testing.T) {
+			// Chạy nhiều lần: thứ tự duyệt map thay đổi nhưng kết quả thì không
+			for run := 0; run < 10; run++ {
+				var got []string
+				for _, cls := range NewClassSorter(tt.order).Sort(sorterFixture()) {
+					got = append(got, cls.Name)
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Fatalf("Sort() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestClassSorterTopologicalCycle(t *testing.T) {
+	a := &models.ClassModel{ID: "a", Name: "A", Relationships: []models.Relationship{{Kind: models.Generalization, Target: "B"}}}
+	b := &models.ClassModel{ID: "b", Name: "B", Relationships: []models.Relationship{{Kind: models.Generalization, Target: "A"}}}
+
+	got := NewClassSorter(OrderTopological).Sort(map[string]*models.ClassModel{"a": a, "b": b})
+	if len(got) != 2 || got[0] != b || got[1] != a {
+		t.Errorf("Sort() on a cycle = %v, want [B A]", got)
+	}
+}
+
+func TestParseClassOrder(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ClassOrder
+		wantErr bool
+	}{
+		{value: "name", want: OrderName},
+		{value: "Position", want: OrderPosition},
+		{value: "TOPOLOGICAL", want: OrderTopological},
+		{value: "size", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseClassOrder(tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("ParseClassOrder(%q) = %q, %v; want %q, error %v", tt.value, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAssignPositions(t *testing.T) {
+	cells := []models.MxCell{
+		{ID: "frame", Geometry: models.MxGeometry{X: "0", Y: "200"}},
+		{ID: "inner", Parent: "frame", Geometry: models.MxGeometry{X: "10", Y: "10"}},
+		{ID: "right", Geometry: models.MxGeometry{X: "300", Y: "0"}},
+		{ID: "left", Geometry: models.MxGeometry{X: "0", Y: "0"}},
+	}
+	classes := map[string]*models.ClassModel{
+		"inner": {ID: "inner", Name: "Inner"},
+		"right": {ID: "right", Name: "Right"},
+		"left":  {ID: "left", Name: "Left"},
+	}
+
+	if next := assignPositions(cells, classes, 5); next != 8 {
+		t.Errorf("assignPositions() = %d, want 8", next)
+	}
+	want := map[string]int{"left": 5, "right": 6, "inner": 7}
+	for id, pos := range want {
+		if classes[id].Position != pos {
+			t.Errorf("%s.Position = %d, want %d", id, classes[id].Position, pos)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"fmt"
+	"nUML/models"
+	"nUML/utils"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reSeparator matches the horizontal rule between compartments (<hr>, <hr size="1"/>...).
+	// reSeparator khớp đường kẻ ngang giữa các ngăn (<hr>, <hr size="1"/>...).
+	reSeparator = regexp.MustCompile(`(?i)<hr[^>]*>`)
+	// reTableRow matches the end of a table row, used when the label is an HTML table without <hr>.
+	// reTableRow khớp phần kết thúc của một hàng bảng, dùng khi nhãn là bảng HTML không có <hr>.
+	reTableRow = regexp.MustCompile(`(?i)</tr\s*>`)
+	// reLineBreak matches tags that end a visual line inside a compartment.
+	// reLineBreak khớp các thẻ kết thúc một dòng hiển thị bên trong một ngăn.
+	reLineBreak = regexp.MustCompile(`(?i)<br\s*/?>|</(p|div|tr|td|th|li)\s*>`)
+	// reTableTag matches table/list tags that CleanHTML does not strip.
+	// reTableTag khớp các thẻ bảng/danh sách mà CleanHTML không loại bỏ.
+	reTableTag = regexp.MustCompile(`(?i)</?(table|tbody|thead|tr|td|th|ul|ol|li)[^>]*>`)
+)
+
+// CompartmentExtractor is responsible for identifying classes drawn as a single cell, where the label
+// holds the name, attributes and operations separated by <hr> or HTML table rows ("Class 2" shapes).
+// CompartmentExtractor chịu trách nhiệm xác định các lớp được vẽ bằng một ô duy nhất, trong đó nhãn
+// chứa tên, thuộc tính và phương thức được phân tách bằng <hr> hoặc các hàng bảng HTML (hình "Class 2").
+type CompartmentExtractor struct {
+	classExtractor   *ClassExtractor
+	featureExtractor *FeatureExtractor
+	diagnostics      *models.Diagnostics
+}
+
+// NewCompartmentExtractor creates a new instance of CompartmentExtractor that reports problems to diagnostics.
+// NewCompartmentExtractor tạo một phiên bản mới của CompartmentExtractor, báo cáo các vấn đề vào diagnostics.
+func NewCompartmentExtractor(diagnostics *models.Diagnostics) *CompartmentExtractor {
+	return &CompartmentExtractor{
+		classExtractor:   NewClassExtractor(diagnostics),
+		featureExtractor: NewFeatureExtractor(diagnostics),
+		diagnostics:      diagnostics,
+	}
+}
+
+// Extract adds single-cell classes (with their fields and methods) to the classes map.
+// Extract thêm các lớp một ô (cùng với trường và phương thức) vào map các lớp.
+func (ce *CompartmentExtractor) Extract(cells []models.MxCell, classes map[string]*models.ClassModel) {
+	for _, cell := range cells {
+		if cell.Vertex != "1" || strings.Contains(cell.Style, "swimlane") || isPackageContainer(cell) {
+			continue
+		}
+		// Bỏ qua các thành viên nằm bên trong một lớp đã biết
+		if _, isMember := classes[cell.Parent]; isMember {
+			continue
+		}
+
+		compartments := ce.splitCompartments(cell.Value)
+		if len(compartments) < 2 {
+			continue
+		}
+
+		// 1. Name compartment (stereotype + name)
+		// 1. Ngăn tên (khuôn mẫu + tên)
+		header := strings.Join(ce.splitLines(compartments[0]), "\n")
+		name, classType := ce.classExtractor.parseClassNameAndType(header)
+		if name == "" {
+			continue
+		}
+
+		cls := &models.ClassModel{
+			ID:             cell.ID,
+			Name:           name,
+			RawName:        utils.CleanHTML(header),
+			Page:           cell.Page,
+			Type:           classType,
+			Stereotype:     parseStereotype(header),
+			TypeParameters: parseTypeParameters(header),
+			Properties:     cell.Properties,
+			Annotations:    parseAnnotations(cell.Properties),
+		}
+		classes[cell.ID] = cls
+		utils.LogVerbose(fmt.Sprintf("Found %s: %s (single cell)", classType, name))
+		reportStereotypeTypo(ce.diagnostics, cell, header, name)
+
+		// 2. Attribute and operation compartments
+		// 2. Các ngăn thuộc tính và phương thức
+		for _, compartment := range compartments[1:] {
+			for _, line := range ce.splitLines(compartment) {
+				ce.featureExtractor.addMember(cls, cell.ID, line, "", nil)
+			}
+		}
+	}
+}
+
+// splitCompartments splits a label into compartments by <hr>, or by table rows when there is no <hr>.
+// splitCompartments tách nhãn thành các ngăn theo <hr>, hoặc theo hàng bảng khi không có <hr>.
+func (ce *CompartmentExtractor) splitCompartments(raw string) []string {
+	if reSeparator.MatchString(raw) {
+		return reSeparator.Split(raw, -1)
+	}
+	if reTableRow.MatchString(raw) {
+		rows := reTableRow.Split(raw, -1)
+		var compartments []string
+		for _, row := range rows {
+			if utils.CleanHTML(reTableTag.ReplaceAllString(row, "")) != "" {
+				compartments = append(compartments, row)
+			}
+		}
+		return compartments
+	}
+	return nil
+}
+
+// splitLines splits a compartment into its visible lines, keeping inline tags such as <i> for abstract detection.
+// splitLines tách một ngăn thành các dòng hiển thị, giữ lại các thẻ nội dòng như <i> để phát hiện trừu tượng.
+func (ce *CompartmentExtractor) splitLines(compartment string) []string {
+	var lines []string
+	text := reLineBreak.ReplaceAllString(compartment, "\n")
+	text = reTableTag.ReplaceAllString(text, "")
+	for _, line := range strings.Split(text, "\n") {
+		if utils.CleanHTML(line) != "" {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	return lines
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"testing"
+)
+
+func TestCompartmentExtractorExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		label   string
+		class   string // Rỗng khi ô không phải là lớp
+		kind    models.ClassType
+		fields  []string
+		methods []string
+	}{
+		{
+			name:    "hr separators",
+			label:   `<p style="margin:0px;text-align:center;"><b>User</b></p><hr size="1"/><p>- name: String<br>- age: int</p><hr size="1"/><p>+ getName(): String</p>`,
+			class:   "User",
+			kind:    models.Class,
+			fields:  []string{"name: String", "age: int"},
+			methods: []string{"getName()"},
+		},
+		{
+			name:    "stereotype in the name compartment",
+			label:   `<p>&lt;&lt;interface&gt;&gt;<br><b>Drawable</b></p><hr><p>+ draw(): void</p>`,
+			class:   "Drawable",
+			kind:    models.Interface,
+			methods: []string{"draw()"},
+		},
+		{
+			name:    "italic name",
+			label:   `<i>Shape</i><hr/><hr/>+ area(): double`,
+			class:   "Shape",
+			kind:    models.Abstract,
+			methods: []string{"area()"},
+		},
+		{
+			name:    "table rows",
+			label:   `<table><tr><td><b>Order</b></td></tr><tr><td>- id: long<br>- total: double</td></tr><tr><td></td></tr><tr><td>+ cancel(): void</td></tr></table>`,
+			class:   "Order",
+			kind:    models.Class,
+			fields:  []string{"id: long", "total: double"},
+			methods: []string{"cancel()"},
+		},
+		{name: "plain label", label: "Just a note"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classes := make(map[string]*models.ClassModel)
+			cell := models.MxCell{ID: "c", Value: tt.label, Style: "verticalAlign=top;html=1;", Vertex: "1"}
+			NewCompartmentExtractor(models.NewDiagnostics()).Extract([]models.MxCell{cell}, classes)
+
+			cls, ok := classes["c"]
+			if tt.class == "" {
+				if ok {
+					t.Errorf("Extract() found class %s, want none", cls.Name)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("Extract() found no class")
+			}
+			if cls.Name != tt.class || cls.Type != tt.kind {
+				t.Errorf("class = %s %s, want %s %s", cls.Type, cls.Name, tt.kind, tt.class)
+			}
+			var fields, methods []string
+			for _, f := range cls.Fields {
+				fields = append(fields, f.Name+": "+f.Type)
+			}
+			for _, m := range cls.Methods {
+				methods = append(methods, m.Signature())
+			}
+			if !reflect.DeepEqual(fields, tt.fields) || !reflect.DeepEqual(methods, tt.methods) {
+				t.Errorf("members = %v %v, want %v %v", fields, methods, tt.fields, tt.methods)
+			}
+		})
+	}
+}
+
+func TestCompartmentExtractorSkipsMembers(t *testing.T) {
+	// Nhãn có <hr> bên trong một lớp swimlane là thành viên của lớp đó, không phải lớp mới
+	classes := map[string]*models.ClassModel{"s": {ID: "s", Name: "Box"}}
+	cells := []models.MxCell{
+		classCell("s", "", "Box"),
+		{ID: "m", Parent: "s", Value: "<b>Note</b><hr>- text: String", Vertex: "1"},
+	}
+	NewCompartmentExtractor(models.NewDiagnostics()).Extract(cells, classes)
+	if len(classes) != 1 {
+		t.Errorf("classes = %v, want only Box", classes)
+	}
+}
@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"nUML/utils"
+	"strconv"
+	"strings"
+)
+
+// EdgeLabelExtractor is responsible for reading the labels draw.io attaches to an edge as child cells
+// (parent = edge id, connectable = 0), such as the multiplicities and role names at each end of an association.
+// EdgeLabelExtractor chịu trách nhiệm đọc các nhãn mà draw.io gắn vào cạnh dưới dạng ô con
+// (parent = id của cạnh, connectable = 0), như bội số và tên vai trò ở mỗi đầu của một liên kết.
+type EdgeLabelExtractor struct{}
+
+// NewEdgeLabelExtractor creates a new instance of EdgeLabelExtractor.
+// NewEdgeLabelExtractor tạo một phiên bản mới của EdgeLabelExtractor.
+func NewEdgeLabelExtractor() *EdgeLabelExtractor {
+	return &EdgeLabelExtractor{}
+}
+
+// Extract returns a relationship per edge (keyed by edge id) holding its middle label and end labels.
+// A child label with a relative x below 0 belongs to the source end, above 0 to the target end.
+// Extract trả về một quan hệ cho mỗi cạnh (khóa là id của cạnh) chứa nhãn giữa và các nhãn ở hai đầu.
+// Nhãn con có x tương đối nhỏ hơn 0 thuộc về đầu nguồn, lớn hơn 0 thuộc về đầu đích.
+func (le *EdgeLabelExtractor) Extract(cells []models.MxCell) map[string]*models.Relationship {
+	relationships := make(map[string]*models.Relationship)
+	for _, cell := range cells {
+		if cell.Edge == "1" {
+			rel := le.relationship(relationships, cell.ID)
+			rel.Label = joinLabel(rel.Label, utils.CleanHTML(cell.Value))
+		}
+	}
+
+	for _, cell := range cells {
+		rel, isLabel := relationships[cell.Parent]
+		if !isLabel || cell.Edge == "1" {
+			continue
+		}
+		text := utils.CleanHTML(cell.Value)
+		if text == "" {
+			continue
+		}
+
+		x, _ := strconv.ParseFloat(cell.Geometry.X, 64)
+		switch {
+		case x < 0:
+			le.applyEnd(&rel.SourceEnd, text)
+		case x > 0:
+			le.applyEnd(&rel.TargetEnd, text)
+		default:
+			rel.Label = joinLabel(rel.Label, text)
+		}
+	}
+	return relationships
+}
+
+// relationship returns the relationship of an edge, creating it on first use.
+// relationship trả về quan hệ của một cạnh, tạo mới ở lần dùng đầu tiên.
+func (le *EdgeLabelExtractor) relationship(relationships map[string]*models.Relationship, edgeID string) *models.Relationship {
+	rel, ok := relationships[edgeID]
+	if !ok {
+		rel = &models.Relationship{EdgeID: edgeID}
+		relationships[edgeID] = rel
+	}
+	return rel
+}
+
+// applyEnd fills an end from a label such as "0..1", "items" or "+items 1..*".
+// Several labels at the same end (one for the multiplicity, one for the role) are combined.
+// applyEnd điền thông tin một đầu từ nhãn như "0..1", "items" hoặc "+items 1..*".
+// Nhiều nhãn ở cùng một đầu (một cho bội số, một cho vai trò) được gộp lại.
+func (le *EdgeLabelExtractor) applyEnd(end *models.RelationshipEnd, text string) {
+	multiplicity, role := parseEdgeLabel(text)
+	if end.Multiplicity == "" {
+		end.Multiplicity = multiplicity
+	}
+	if end.Role == "" {
+		end.Role = role
+	}
+}
+
+// joinLabel appends a label to an existing one, separated by a space.
+// joinLabel nối thêm một nhãn vào nhãn hiện có, phân tách bằng dấu cách.
+func joinLabel(label, text string) string {
+	return strings.TrimSpace(label + " " + text)
+}
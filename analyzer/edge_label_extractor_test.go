@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"testing"
+)
+
+// edgeLabelCell builds a label draw.io attaches to an edge, at relative position x (-1 source, 1 target).
+// edgeLabelCell tạo một nhãn mà draw.io gắn vào cạnh, tại vị trí tương đối x (-1 nguồn, 1 đích).
+func edgeLabelCell(id, edge, value, x string) models.MxCell {
+	return models.MxCell{ID: id, Parent: edge, Value: value, Style: "edgeLabel;resizable=0;", Vertex: "1", Connectable: "0",
+		Geometry: models.MxGeometry{X: x, Relative: "1"}}
+}
+
+func TestEdgeLabelExtractorExtract(t *testing.T) {
+	edge := edgeCell("e", "endArrow=none;", "o", "l")
+	edge.Value = "contains"
+	cells := []models.MxCell{
+		edge,
+		edgeLabelCell("e-src", "e", "1", "-0.8"),
+		edgeLabelCell("e-role", "e", "+lines", "0.9"),
+		edgeLabelCell("e-mult", "e", "<b>1..*</b>", "0.7"),
+		edgeLabelCell("e-mid", "e", "ordered", "0"),
+		edgeLabelCell("e-empty", "e", "", "0.5"),
+		// Ô có cha không phải là cạnh không phải là nhãn
+		edgeLabelCell("other", "o", "2", "0.5"),
+	}
+
+	got := NewEdgeLabelExtractor().Extract(cells)
+	want := map[string]*models.Relationship{
+		"e": {
+			EdgeID:    "e",
+			Label:     "contains ordered",
+			SourceEnd: models.RelationshipEnd{Multiplicity: "1"},
+			TargetEnd: models.RelationshipEnd{Multiplicity: "1..*", Role: "lines"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Extract() = %+v, want %+v", got["e"], want["e"])
+	}
+}
+
+func TestEdgeLabelsToFields(t *testing.T) {
+	tests := []struct {
+		name   string
+		style  string
+		labels []models.MxCell
+		want   []string
+	}{
+		{
+			name:   "label at the target end",
+			style:  "endArrow=open;",
+			labels: []models.MxCell{edgeLabelCell("a", "e", "1", "-0.9"), edgeLabelCell("b", "e", "* lines", "0.9")},
+			want:   []string{"Order.lines: List<Line>"},
+		},
+		{
+			name:   "field on the target reads the source end",
+			style:  "startArrow=open;endArrow=none;",
+			labels: []models.MxCell{edgeLabelCell("a", "e", "0..1 owner", "-0.9"), edgeLabelCell("b", "e", "*", "0.9")},
+			want:   []string{"Line.owner: Order"},
+		},
+		{
+			name:   "plain line",
+			style:  "endArrow=none;",
+			labels: []models.MxCell{edgeLabelCell("a", "e", "items", "0.5")},
+			want:   []string{"Order.items: Line"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classes := relationshipFixture()
+			cells := append([]models.MxCell{edgeCell("e", tt.style, "o", "l")}, tt.labels...)
+			NewRelationshipExtractor(models.NewDiagnostics()).Extract(cells, classes)
+			if got := associationFields(classes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
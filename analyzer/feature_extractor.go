@@ -1,19 +1,32 @@
 package analyzer
 
 import (
+	"fmt"
 	"nUML/models"
 	"nUML/utils"
+	"regexp"
 	"strings"
 )
 
+var (
+	// reThrowsProperty matches a UML property string such as {throws=IOException, SQLException}.
+	// reThrowsProperty khớp một chuỗi thuộc tính UML như {throws=IOException, SQLException}.
+	reThrowsProperty = regexp.MustCompile(`\{\s*throws\s*=\s*([^}]*)\}`)
+	// reThrowsClause matches a trailing Java-style "throws A, B" clause.
+	// reThrowsClause khớp mệnh đề "throws A, B" kiểu Java ở cuối.
+	reThrowsClause = regexp.MustCompile(`\s+throws\s+(.+)$`)
+)
+
 // FeatureExtractor is responsible for extracting fields and methods from the diagram.
 // FeatureExtractor chịu trách nhiệm trích xuất các trường và phương thức từ biểu đồ.
-type FeatureExtractor struct{}
+type FeatureExtractor struct {
+	diagnostics *models.Diagnostics
+}
 
-// NewFeatureExtractor creates a new instance of FeatureExtractor.
-// NewFeatureExtractor tạo một phiên bản mới của FeatureExtractor.
-func NewFeatureExtractor() *FeatureExtractor {
-	return &FeatureExtractor{}
+// NewFeatureExtractor creates a new instance of FeatureExtractor that reports problems to diagnostics.
+// NewFeatureExtractor tạo một phiên bản mới của FeatureExtractor, báo cáo các vấn đề vào diagnostics.
+func NewFeatureExtractor(diagnostics *models.Diagnostics) *FeatureExtractor {
+	return &FeatureExtractor{diagnostics: diagnostics}
 }
 
 // Extract identifies fields and methods inside swimlanes.
@@ -27,36 +40,145 @@ func (fe *FeatureExtractor) Extract(cells []models.MxCell, classes map[string]*m
 			if ok {
 				// It's a field or method or separator
 				// Nó là một trường hoặc phương thức hoặc dấu phân cách
-				val := utils.CleanHTML(cell.Value)
-				rawVal := cell.Value
-				if val == "" {
-					continue
-				}
+				fe.addMember(parentClass, cell.ID, cell.Value, cell.Style, cell.Properties)
+				fe.checkNotation(parentClass, cell)
+			}
+		}
+	}
+}
 
-				// Identify if Method or Field based on parenthesis
-				// Xác định xem là Phương thức hay Trường dựa trên dấu ngoặc đơn
-				if strings.Contains(val, "(") && strings.Contains(val, ")") {
-					// Method
-					m := fe.parseMethod(rawVal) // Pass RAW for italics check
-					// nếu lớp cha là interface, thì tất cả phương thức đều là abstract và public
-					if parentClass.Type == models.Interface {
-						m.IsAbstract = true
-						m.Visibility = "public" // Force public for interface
-					}
-					parentClass.Methods = append(parentClass.Methods, m)
-				} else {
-					// Field (if not just a line separator)
-					// Trường (nếu không chỉ là dòng phân cách)
-					if !strings.Contains(cell.Style, "line") {
-						f := fe.parseField(val)
-						parentClass.Fields = append(parentClass.Fields, f)
-					}
+// addMember parses one member label (raw HTML) and appends it to the class as a field or method.
+// cellID locates the member in the diagram for diagnostics.
+// addMember phân tích nhãn của một thành viên (HTML thô) và thêm nó vào lớp dưới dạng trường hoặc phương thức.
+// cellID định vị thành viên trong biểu đồ cho các chẩn đoán.
+func (fe *FeatureExtractor) addMember(parentClass *models.ClassModel, cellID string, rawVal string, style string, props map[string]string) {
+	val := utils.CleanHTML(rawVal)
+	if val == "" {
+		return
+	}
+
+	// Identify if Method or Field based on parenthesis
+	// Xác định xem là Phương thức hay Trường dựa trên dấu ngoặc đơn
+	if strings.Contains(val, "(") && strings.Contains(val, ")") {
+		// Method
+		m := fe.parseMethod(rawVal) // Pass RAW for italics check
+		m.Annotations = parseAnnotations(props)
+		if throws, ok := props["throws"]; ok {
+			m.Throws = appendThrows(m.Throws, throws)
+		}
+		// nếu lớp cha là interface, thì tất cả phương thức đều là abstract và public
+		if parentClass.Type == models.Interface {
+			m.IsAbstract = true
+			m.Visibility = "public" // Force public for interface
+		}
+		parentClass.Methods = append(parentClass.Methods, m)
+	} else {
+		// Field (if not just a line separator)
+		// Trường (nếu không chỉ là dòng phân cách)
+		if !strings.Contains(style, "line") {
+			f := fe.parseField(val)
+			if f.Type == "" {
+				f.Type = "String" // Default
+				// Hằng số enum và dòng "getters/setters" không cần kiểu
+				if parentClass.Type != models.Enum && !strings.Contains(strings.ToLower(val), "getters/setters") {
+					fe.diagnostics.Report(models.SeverityWarning, models.RuleUntypedField, cellID, parentClass.Page,
+						fmt.Sprintf("Field %s.%s has no type, String is assumed (trường không có kiểu)", parentClass.Name, f.Name))
 				}
 			}
+			f.Annotations = parseAnnotations(props)
+			parentClass.Fields = append(parentClass.Fields, f)
 		}
 	}
 }
 
+// checkNotation reports a member cell written in Java notation ("String name", "void run()") with a fix
+// rewriting it in UML notation ("name: String", "run(): void"). Only plain text labels are rewritten.
+// checkNotation báo cáo một ô thành viên được viết theo ký pháp Java ("String name", "void run()") kèm
+// chỉnh sửa viết lại theo ký pháp UML ("name: String", "run(): void"). Chỉ nhãn văn bản thuần được ghi lại.
+func (fe *FeatureExtractor) checkNotation(parentClass *models.ClassModel, cell models.MxCell) {
+	if strings.Contains(cell.Style, "line") || cell.Properties["placeholders"] == "1" {
+		return
+	}
+	// Nhãn HTML chứa thẻ hoặc thực thể không thể ghi lại an toàn từ văn bản đã làm sạch
+	if strings.Contains(cell.Style, "html=1") && strings.ContainsAny(cell.Value, "<&") {
+		return
+	}
+	fixed := umlNotation(strings.TrimSpace(cell.Value))
+	if fixed == "" {
+		return
+	}
+	fe.diagnostics.ReportFix(models.SeverityNote, models.RuleMemberNotation, cell.ID, cell.Page,
+		fmt.Sprintf("Member %q of %s is in Java notation, UML is %q (ký pháp Java)", strings.TrimSpace(cell.Value), parentClass.Name, fixed),
+		models.Fix{Page: cell.Page, CellID: cell.ID, Attribute: "value", Value: fixed})
+}
+
+// umlNotation rewrites a member in Java notation into UML notation, keeping the visibility marker,
+// modifiers, initial value and throws clause. It returns "" when the member is not in Java notation.
+// umlNotation viết lại một thành viên theo ký pháp Java thành ký pháp UML, giữ ký hiệu phạm vi,
+// các từ khóa sửa đổi, giá trị khởi tạo và mệnh đề throws. Trả về "" khi thành viên không theo ký pháp Java.
+func umlNotation(val string) string {
+	prefix, rest := "", val
+	if rest != "" && strings.ContainsAny(rest[:1], "+-#~") {
+		trimmed := strings.TrimLeft(rest[1:], " ")
+		prefix, rest = rest[:len(rest)-len(trimmed)], trimmed
+	}
+
+	// splitDeclaration tách "static final Map<K, V> name" thành từ khóa sửa đổi, kiểu và tên
+	splitDeclaration := func(head string) (mods, typ, name string) {
+		words := strings.Fields(head)
+		i := 0
+		for i < len(words) && javaModifiers[words[i]] {
+			i++
+		}
+		if len(words)-i < 2 {
+			return "", "", ""
+		}
+		name = words[len(words)-1]
+		if !reIdentifier.MatchString(name) || !reIdentifier.MatchString(reTypeIdentifier.FindString(words[i])) {
+			return "", "", ""
+		}
+		if i > 0 {
+			mods = strings.Join(words[:i], " ") + " "
+		}
+		return mods, strings.Join(words[i:len(words)-1], " "), name
+	}
+
+	open, close := strings.Index(rest, "("), strings.LastIndex(rest, ")")
+	if open != -1 && close > open {
+		// Method: "ReturnType name(params) throws X" -> "name(params): ReturnType throws X"
+		after := rest[close+1:]
+		if strings.Contains(after, ":") {
+			return ""
+		}
+		mods, typ, name := splitDeclaration(rest[:open])
+		if name == "" {
+			return ""
+		}
+		return prefix + mods + name + rest[open:close+1] + ": " + typ + after
+	}
+
+	// Field: "Type name = value" -> "name: Type = value"
+	if strings.Contains(rest, ":") {
+		return ""
+	}
+	head, init := rest, ""
+	if idx := strings.Index(rest, "="); idx != -1 {
+		head, init = rest[:idx], " = "+strings.TrimSpace(rest[idx+1:])
+	}
+	mods, typ, name := splitDeclaration(head)
+	if name == "" {
+		return ""
+	}
+	return prefix + mods + name + ": " + typ + init
+}
+
+// javaModifiers lists the modifier keywords that may precede a member type in Java notation.
+// javaModifiers liệt kê các từ khóa sửa đổi có thể đứng trước kiểu của thành viên trong ký pháp Java.
+var javaModifiers = map[string]bool{
+	"public": true, "private": true, "protected": true, "static": true, "final": true, "abstract": true,
+	"default": true, "transient": true, "volatile": true, "synchronized": true,
+}
+
 // parseField parses a string into a Field struct.
 // parseField phân tích một chuỗi thành cấu trúc Field.
 func (fe *FeatureExtractor) parseField(val string) models.Field {
@@ -111,8 +233,8 @@ func (fe *FeatureExtractor) parseField(val string) models.Field {
 			f.Type = partsSpace[0]
 			f.Name = partsSpace[1]
 		} else {
+			// Kiểu mặc định được gán bởi addMember
 			f.Name = cleanVal
-			f.Type = "String" // Default
 		}
 	}
 
@@ -170,6 +292,19 @@ func (fe *FeatureExtractor) parseMethod(rawVal string) models.Method {
 	}
 	cleanVal = strings.TrimSpace(cleanVal)
 
+	// Checked exceptions: "{throws=X}" property or "throws X" clause after the signature
+	// Ngoại lệ được kiểm tra: thuộc tính "{throws=X}" hoặc mệnh đề "throws X" sau chữ ký
+	if match := reThrowsProperty.FindStringSubmatch(cleanVal); match != nil {
+		m.Throws = appendThrows(m.Throws, match[1])
+		cleanVal = strings.TrimSpace(reThrowsProperty.ReplaceAllString(cleanVal, ""))
+	}
+	if lastParen := strings.LastIndex(cleanVal, ")"); lastParen != -1 {
+		if loc := reThrowsClause.FindStringSubmatchIndex(cleanVal[lastParen:]); loc != nil {
+			m.Throws = appendThrows(m.Throws, cleanVal[lastParen+loc[2]:])
+			cleanVal = strings.TrimSpace(cleanVal[:lastParen+loc[0]])
+		}
+	}
+
 	// Check for Return Type: ": Type" AFTER the closing parenthesis
 	// Kiểm tra Kiểu trả về: ": Type" SAU dấu ngoặc đóng
 	lastParen := strings.LastIndex(cleanVal, ")")
@@ -198,7 +333,7 @@ func (fe *FeatureExtractor) parseMethod(rawVal string) models.Method {
 
 		if parenStart != -1 {
 			m.Name = strings.TrimSpace(lhs[:parenStart])
-			m.Parameters = lhs[parenStart+1 : lastParen]
+			m.Parameters = fe.parseParameters(lhs[parenStart+1 : lastParen])
 		} else {
 			m.Name = lhs
 		}
@@ -212,3 +347,92 @@ func (fe *FeatureExtractor) parseMethod(rawVal string) models.Method {
 
 	return m
 }
+
+// parseParameters splits a parameter list at top-level commas, so generic types such as
+// Map<String, List<Integer>> stay whole. Both "name: Type = default" (UML) and "Type name" (Java) are accepted.
+// parseParameters tách danh sách tham số tại các dấu phẩy cấp cao nhất, để các kiểu tổng quát như
+// Map<String, List<Integer>> được giữ nguyên. Chấp nhận cả "tên: Kiểu = mặc_định" (UML) và "Kiểu tên" (Java).
+func (fe *FeatureExtractor) parseParameters(raw string) []models.Parameter {
+	var params []models.Parameter
+	for _, part := range utils.SplitTopLevel(raw, ',') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		params = append(params, fe.parseParameter(part))
+	}
+	return params
+}
+
+// parseParameter parses a single parameter such as "m: Map<String, X>", "in id: int", "String... args" or "int xs[]".
+// parseParameter phân tích một tham số như "m: Map<String, X>", "in id: int", "String... args" hoặc "int xs[]".
+func (fe *FeatureExtractor) parseParameter(raw string) models.Parameter {
+	var p models.Parameter
+
+	// Default value: "count: int = 10"
+	// Giá trị mặc định: "count: int = 10"
+	if parts := utils.SplitTopLevel(raw, '='); len(parts) > 1 {
+		p.Default = strings.TrimSpace(strings.Join(parts[1:], "="))
+		raw = parts[0]
+	}
+
+	// UML direction and Java modifiers carry no type information
+	// Hướng tham số UML và từ khóa Java không mang thông tin kiểu
+	var tokens []string
+	for _, t := range utils.SplitTopLevel(strings.TrimSpace(raw), ' ') {
+		switch t = strings.TrimSpace(t); t {
+		case "", "in", "out", "inout", "final":
+		default:
+			tokens = append(tokens, t)
+		}
+	}
+	raw = strings.Join(tokens, " ")
+
+	if parts := utils.SplitTopLevel(raw, ':'); len(parts) > 1 {
+		// UML: name: Type
+		p.Name = strings.TrimSpace(parts[0])
+		p.Type = strings.TrimSpace(strings.Join(parts[1:], ":"))
+	} else if len(tokens) >= 2 {
+		// Java: Type name (the type may contain spaces, e.g. "? extends T")
+		// Java: Kiểu tên (kiểu có thể chứa khoảng trắng, ví dụ "? extends T")
+		p.Name = tokens[len(tokens)-1]
+		p.Type = strings.Join(tokens[:len(tokens)-1], " ")
+	} else {
+		p.Type = raw
+	}
+
+	// C-style array declarator: "int xs[]" -> int[] xs
+	// Khai báo mảng kiểu C: "int xs[]" -> int[] xs
+	for strings.HasSuffix(p.Name, "[]") {
+		p.Name = strings.TrimSuffix(p.Name, "[]")
+		p.Type += "[]"
+	}
+	p.Name = strings.TrimSpace(p.Name)
+
+	if t, err := models.ParseType(p.Type); err == nil {
+		p.Type = t.String()
+	}
+	return p
+}
+
+// appendThrows adds the exception names of a comma separated list, skipping duplicates.
+// appendThrows thêm tên các ngoại lệ từ danh sách phân tách bằng dấu phẩy, bỏ qua các tên trùng.
+func appendThrows(throws []string, list string) []string {
+	for _, name := range utils.SplitTopLevel(list, ',') {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		exists := false
+		for _, t := range throws {
+			if t == name {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			throws = append(throws, name)
+		}
+	}
+	return throws
+}
@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"testing"
+)
+
+func TestFeatureExtractorParseMethod(t *testing.T) {
+	tests := []struct {
+		raw        string
+		name       string
+		returnType string
+		params     []models.Parameter
+	}{
+		{
+			raw:        "+ put(m: Map<String, List<Integer>>, k: String): void",
+			name:       "put",
+			returnType: "void",
+			params:     []models.Parameter{{Name: "m", Type: "Map<String, List<Integer>>"}, {Name: "k", Type: "String"}},
+		},
+		{
+			raw:        "+ format(String pattern, Object... args) : String",
+			name:       "format",
+			returnType: "String",
+			params:     []models.Parameter{{Name: "pattern", Type: "String"}, {Name: "args", Type: "Object..."}},
+		},
+		{
+			raw:        "# copy(in src: List<? extends T>, final int xs[], count: int = 10)",
+			name:       "copy",
+			returnType: "void",
+			params:     []models.Parameter{{Name: "src", Type: "List<? extends T>"}, {Name: "xs", Type: "int[]"}, {Name: "count", Type: "int", Default: "10"}},
+		},
+		{
+			raw:        "+ add(int, int): int",
+			name:       "add",
+			returnType: "int",
+			params:     []models.Parameter{{Type: "int"}, {Type: "int"}},
+		},
+		{
+			raw:        "+ toArray(): int[][]",
+			name:       "toArray",
+			returnType: "int[][]",
+		},
+	}
+
+	fe := NewFeatureExtractor(models.NewDiagnostics())
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			m := fe.parseMethod(tt.raw)
+			if m.Name != tt.name || m.ReturnType != tt.returnType {
+				t.Errorf("parseMethod() = %s: %s, want %s: %s", m.Name, m.ReturnType, tt.name, tt.returnType)
+			}
+			if !reflect.DeepEqual(m.Parameters, tt.params) {
+				t.Errorf("parameters = %+v, want %+v", m.Parameters, tt.params)
+			}
+		})
+	}
+}
+
+func TestFeatureExtractorParseThrows(t *testing.T) {
+	tests := []struct {
+		raw        string
+		returnType string
+		throws     []string
+	}{
+		{raw: "+ load(id: long): User throws NotFoundException", returnType: "User", throws: []string{"NotFoundException"}},
+		{raw: "+ load(id: long): User {throws=IOException, SQLException}", returnType: "User", throws: []string{"IOException", "SQLException"}},
+		{raw: "+ save(u: User) throws IOException, IOException", returnType: "void", throws: []string{"IOException"}},
+		{raw: "+ parse(text: String): Map<String, List<Integer>> throws ParseException", returnType: "Map<String, List<Integer>>", throws: []string{"ParseException"}},
+		// "throws" trong tên tham số không phải là mệnh đề throws
+		{raw: "+ count(throwsCount: int): int", returnType: "int"},
+	}
+
+	fe := NewFeatureExtractor(models.NewDiagnostics())
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			m := fe.parseMethod(tt.raw)
+			if m.ReturnType != tt.returnType || !reflect.DeepEqual(m.Throws, tt.throws) {
+				t.Errorf("parseMethod() = %s throws %v, want %s throws %v", m.ReturnType, m.Throws, tt.returnType, tt.throws)
+			}
+		})
+	}
+}
+
+func TestFeatureExtractorThrowsProperty(t *testing.T) {
+	cls := &models.ClassModel{Name: "UserService"}
+	// Thuộc tính "throws" của hình được gộp với mệnh đề trong nhãn, không lặp lại
+	NewFeatureExtractor(models.NewDiagnostics()).addMember(cls, "m", "+ load(id: long): User throws NotFoundException", "",
+		map[string]string{"throws": "IOException, NotFoundException"})
+	if len(cls.Methods) != 1 {
+		t.Fatalf("methods = %+v, want load", cls.Methods)
+	}
+	if got, want := cls.Methods[0].Throws, []string{"NotFoundException", "IOException"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("throws = %v, want %v", got, want)
+	}
+}
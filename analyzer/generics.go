@@ -0,0 +1,195 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"nUML/utils"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reBinding matches one template binding of a <<bind>> label, e.g. "T -> User" or "ID=Long".
+	// reBinding khớp một phép gán mẫu của nhãn <<bind>>, ví dụ "T -> User" hoặc "ID=Long".
+	reBinding = regexp.MustCompile(`^([A-Za-z_$][\w$]*)\s*(?:->|=|:)\s*(.+)$`)
+	// reTypeIdentifier matches an identifier inside a type expression.
+	// reTypeIdentifier khớp một định danh bên trong biểu thức kiểu.
+	reTypeIdentifier = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+)
+
+// splitGenerics splits "Repository<T, ID>" into "Repository" and "T, ID". Stereotypes (<<interface>>)
+// are skipped, and text without a generic part is returned unchanged with an empty list.
+// splitGenerics tách "Repository<T, ID>" thành "Repository" và "T, ID". Các khuôn mẫu (<<interface>>)
+// được bỏ qua, văn bản không có phần tổng quát được trả về nguyên vẹn cùng danh sách rỗng.
+func splitGenerics(text string) (string, string) {
+	for i := 0; i < len(text); i++ {
+		if text[i] != '<' {
+			continue
+		}
+		if strings.HasPrefix(text[i:], "<<") {
+			// Bỏ qua khuôn mẫu <<...>>
+			end := strings.Index(text[i:], ">>")
+			if end == -1 {
+				return text, ""
+			}
+			i += end + 1
+			continue
+		}
+		depth := 0
+		for j := i; j < len(text); j++ {
+			switch text[j] {
+			case '<':
+				depth++
+			case '>':
+				if j > 0 && text[j-1] == '-' {
+					// Mũi tên "->" trong nhãn <<bind>> không đóng ngoặc
+					continue
+				}
+				depth--
+				if depth == 0 {
+					return strings.TrimSpace(text[:i] + text[j+1:]), strings.TrimSpace(text[i+1 : j])
+				}
+			}
+		}
+		return text, ""
+	}
+	return text, ""
+}
+
+// parseTypeParameters reads the generic parameters of a class label such as
+// "Repository<T, ID extends Serializable>" (stereotypes are ignored).
+// parseTypeParameters đọc các tham số tổng quát của nhãn lớp như
+// "Repository<T, ID extends Serializable>" (bỏ qua các khuôn mẫu).
+func parseTypeParameters(raw string) []models.TypeParameter {
+	_, list := splitGenerics(utils.CleanHTML(raw))
+	return parseTypeParameterList(list)
+}
+
+// parseTypeParameterList parses "T, ID extends Serializable & Comparable<ID>" (UML "T: Number" is accepted too).
+// parseTypeParameterList phân tích "T, ID extends Serializable & Comparable<ID>" (chấp nhận cả dạng UML "T: Number").
+func parseTypeParameterList(list string) []models.TypeParameter {
+	var params []models.TypeParameter
+	for _, part := range utils.SplitTopLevel(list, ',') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var tp models.TypeParameter
+		bounds := ""
+		if idx := strings.Index(part, " extends "); idx != -1 {
+			tp.Name, bounds = part[:idx], part[idx+len(" extends "):]
+		} else if parts := utils.SplitTopLevel(part, ':'); len(parts) == 2 {
+			tp.Name, bounds = parts[0], parts[1]
+		} else {
+			tp.Name = part
+		}
+		tp.Name = utils.SanitizeName(tp.Name)
+		for _, b := range utils.SplitTopLevel(bounds, '&') {
+			if b = strings.TrimSpace(b); b != "" {
+				tp.Bounds = append(tp.Bounds, b)
+			}
+		}
+		if tp.Name != "" {
+			params = append(params, tp)
+		}
+	}
+	return params
+}
+
+// parseTypeArguments reads the type arguments an edge label supplies for its target:
+// "<<bind>> T->User, ID->Long" (ordered by the target's parameters), "<User, Long>" or "Base<User>".
+// parseTypeArguments đọc các đối số kiểu mà nhãn của cạnh cung cấp cho đích:
+// "<<bind>> T->User, ID->Long" (sắp theo tham số của đích), "<User, Long>" hoặc "Base<User>".
+func parseTypeArguments(label string, target *models.ClassModel) []string {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return nil
+	}
+
+	if stereo := reStereotype.FindStringSubmatch(label); stereo != nil && strings.EqualFold(strings.TrimSpace(stereo[2]), "bind") {
+		body := strings.TrimSpace(reStereotype.ReplaceAllString(label, ""))
+		if _, list := splitGenerics(body); list != "" {
+			body = list
+		}
+		bindings := make(map[string]string)
+		var ordered []string
+		for _, part := range utils.SplitTopLevel(body, ',') {
+			if m := reBinding.FindStringSubmatch(strings.TrimSpace(part)); m != nil {
+				bindings[m[1]] = strings.TrimSpace(m[2])
+				ordered = append(ordered, strings.TrimSpace(m[2]))
+			}
+		}
+		if len(target.TypeParameters) == 0 {
+			return ordered
+		}
+		var args []string
+		for _, tp := range target.TypeParameters {
+			arg, ok := bindings[tp.Name]
+			if !ok {
+				arg = "Object"
+			}
+			args = append(args, arg)
+		}
+		return args
+	}
+
+	// Explicit text: "<User>" or "Base<User>"
+	// Văn bản rõ ràng: "<User>" hoặc "Base<User>"
+	if name, list := splitGenerics(label); list != "" && (name == "" || name == target.Name) {
+		return splitTypeArguments(list)
+	}
+	return nil
+}
+
+// splitTypeArguments splits "User, Map<String, Long>" into its top-level type arguments.
+// splitTypeArguments tách "User, Map<String, Long>" thành các đối số kiểu cấp cao nhất.
+func splitTypeArguments(list string) []string {
+	var args []string
+	for _, a := range utils.SplitTopLevel(list, ',') {
+		if a = strings.TrimSpace(a); a != "" {
+			args = append(args, a)
+		}
+	}
+	return args
+}
+
+// bindTypeArguments pairs the type parameters of a supertype with the arguments of the relationship.
+// bindTypeArguments ghép các tham số kiểu của kiểu cha với các đối số của quan hệ.
+func bindTypeArguments(params []models.TypeParameter, args []string) map[string]string {
+	bindings := make(map[string]string)
+	for i, tp := range params {
+		if i < len(args) {
+			bindings[tp.Name] = args[i]
+		}
+	}
+	return bindings
+}
+
+// substituteType replaces type parameters inside a type expression: List<T> with T=User becomes List<User>.
+// substituteType thay thế các tham số kiểu bên trong biểu thức kiểu: List<T> với T=User trở thành List<User>.
+func substituteType(t string, bindings map[string]string) string {
+	if len(bindings) == 0 {
+		return t
+	}
+	return reTypeIdentifier.ReplaceAllStringFunc(t, func(id string) string {
+		if arg, ok := bindings[id]; ok {
+			return arg
+		}
+		return id
+	})
+}
+
+// substituteMethod applies substituteType to the return type and parameter types of an inherited method.
+// substituteMethod áp dụng substituteType cho kiểu trả về và các kiểu tham số của một phương thức được kế thừa.
+func substituteMethod(m models.Method, bindings map[string]string) models.Method {
+	if len(bindings) == 0 {
+		return m
+	}
+	m.ReturnType = substituteType(m.ReturnType, bindings)
+	params := make([]models.Parameter, len(m.Parameters))
+	for i, p := range m.Parameters {
+		p.Type = substituteType(p.Type, bindings)
+		params[i] = p
+	}
+	m.Parameters = params
+	return m
+}
@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"testing"
+)
+
+func TestSplitGenerics(t *testing.T) {
+	tests := []struct {
+		text, name, list string
+	}{
+		{text: "Repository<T, ID>", name: "Repository", list: "T, ID"},
+		{text: "<<interface>> Repository<T extends Comparable<T>>", name: "<<interface>> Repository", list: "T extends Comparable<T>"},
+		{text: "<<bind>> <T -> User>", name: "<<bind>>", list: "T -> User"},
+		{text: "User", name: "User"},
+		{text: "Broken<T", name: "Broken<T"},
+	}
+	for _, tt := range tests {
+		if name, list := splitGenerics(tt.text); name != tt.name || list != tt.list {
+			t.Errorf("splitGenerics(%q) = %q, %q; want %q, %q", tt.text, name, list, tt.name, tt.list)
+		}
+	}
+}
+
+func TestParseTypeParameters(t *testing.T) {
+	got := parseTypeParameters("<b>Repository&lt;T, ID extends Serializable &amp; Comparable&lt;ID&gt;, N: Number&gt;</b>")
+	want := []models.TypeParameter{
+		{Name: "T"},
+		{Name: "ID", Bounds: []string{"Serializable", "Comparable<ID>"}},
+		{Name: "N", Bounds: []string{"Number"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseTypeParameters() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseTypeArguments(t *testing.T) {
+	repository := &models.ClassModel{Name: "Repository", TypeParameters: []models.TypeParameter{{Name: "T"}, {Name: "ID"}}}
+	tests := []struct {
+		label string
+		want  []string
+	}{
+		{label: "<<bind>> T->User, ID->Long", want: []string{"User", "Long"}},
+		// Thứ tự theo tham số của đích, không theo nhãn; tham số thiếu trở thành Object
+		{label: "«bind» ID = Long", want: []string{"Object", "Long"}},
+		{label: "<<bind>> <T -> Map<String, User>, ID : Long>", want: []string{"Map<String, User>", "Long"}},
+		{label: "<User, Long>", want: []string{"User", "Long"}},
+		{label: "Repository<User, Long>", want: []string{"User", "Long"}},
+		{label: "Other<User>"},
+		{label: "uses"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			if got := parseTypeArguments(tt.label, repository); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTypeArguments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubstituteType(t *testing.T) {
+	bindings := map[string]string{"T": "User", "ID": "Long"}
+	tests := map[string]string{
+		"T":                 "User",
+		"List<T>":           "List<User>",
+		"Map<ID, List<T>>":  "Map<Long, List<User>>",
+		"Type":              "Type",
+		"Optional<TypeT>[]": "Optional<TypeT>[]",
+	}
+	for in, want := range tests {
+		if got := substituteType(in, bindings); got != want {
+			t.Errorf("substituteType(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBindEdgeSubstitution(t *testing.T) {
+	cells := []models.MxCell{
+		classCell("r", "", "&lt;&lt;interface&gt;&gt;<br>Repository&lt;T, ID&gt;"),
+		memberCell("r1", "r", "+ save(item: T): T"),
+		memberCell("r2", "r", "+ findAll(ids: List&lt;ID&gt;): List&lt;T&gt;"),
+		classCell("u", "", "UserRepository"),
+		classCell("m", "", "User"),
+		edgeCell("e", "endArrow=block;endFill=0;dashed=1;", "u", "r"),
+		{ID: "e-label", Parent: "e", Value: "&lt;&lt;bind&gt;&gt; T-&gt;User, ID-&gt;Long", Vertex: "1", Connectable: "0", Geometry: models.MxGeometry{Relative: "1"}},
+	}
+	as := NewAnalyzerService(OrderName)
+	var userRepository *models.ClassModel
+	for _, cls := range as.AnalyzeDiagram(cells) {
+		if cls.Name == "UserRepository" {
+			userRepository = cls
+		}
+	}
+	if userRepository == nil {
+		t.Fatalf("UserRepository not found")
+	}
+
+	if rels := userRepository.Relationships; len(rels) != 1 || rels[0].TargetType() != "Repository<User, Long>" {
+		t.Errorf("relationships = %+v, want implements Repository<User, Long>", rels)
+	}
+	// Các stub được sinh với đối số kiểu đã thay thế
+	var methods []string
+	for _, m := range userRepository.Methods {
+		methods = append(methods, m.Signature()+": "+m.ReturnType)
+	}
+	if want := []string{"save(User): User", "findAll(List<Long>): List<User>"}; !reflect.DeepEqual(methods, want) {
+		t.Errorf("methods = %v, want %v", methods, want)
+	}
+}
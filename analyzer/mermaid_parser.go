@@ -0,0 +1,340 @@
+package analyzer
+
+import (
+	"fmt"
+	"nUML/models"
+	"nUML/utils"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reMermaidFence matches a fenced ```mermaid block in Markdown.
+	// reMermaidFence khớp một khối ```mermaid trong Markdown.
+	reMermaidFence = regexp.MustCompile("(?s)```\\s*mermaid[^\\n]*\\n(.*?)```")
+	// reMermaidClass matches "class Name", "class Name~T~ {", `class Name["Label"]` ...
+	// reMermaidClass khớp "class Name", "class Name~T~ {", `class Name["Label"]` ...
+	reMermaidClass = regexp.MustCompile(`^class\s+([\w$]+)(~[^{]*~)?(?:\["[^"]*"\])?(?::::[\w-]+)?\s*(\{)?\s*(\})?$`)
+	// reMermaidAnnotation matches "<<interface>>" optionally followed by a class name.
+	// reMermaidAnnotation khớp "<<interface>>" có thể theo sau là tên lớp.
+	reMermaidAnnotation = regexp.MustCompile(`^<<\s*([\w ]+?)\s*>>\s*([\w$]*)$`)
+	// reMermaidRelation matches `A "1" *-- "*" B : label`.
+	// reMermaidRelation khớp `A "1" *-- "*" B : nhãn`.
+	reMermaidRelation = regexp.MustCompile(`^([\w$]+)\s*(?:"([^"]*)")?\s*(<\|--|--\|>|<\|\.\.|\.\.\|>|\*--|--\*|o--|--o|-->|<--|\.\.>|<\.\.|--|\.\.)\s*(?:"([^"]*)")?\s*([\w$]+)\s*(?::\s*(.*))?$`)
+	// reMermaidMember matches "Name : +member".
+	// reMermaidMember khớp "Name : +thành_viên".
+	reMermaidMember = regexp.MustCompile(`^([\w$]+)\s*:\s*(.+)$`)
+	// reIdentifier matches a single Java identifier.
+	// reIdentifier khớp một định danh Java duy nhất.
+	reIdentifier = regexp.MustCompile(`^[A-Za-z_$][\w$]*$`)
+)
+
+// mermaidRelation is a relationship line collected while parsing, applied once all classes are known.
+// mermaidRelation là một dòng quan hệ được thu thập khi phân tích, áp dụng sau khi đã biết tất cả các lớp.
+type mermaidRelation struct {
+	left, right         string
+	leftCard, rightCard string
+	arrow               string
+	label               string
+}
+
+// MermaidParser is responsible for building class models from Mermaid classDiagram sources.
+// MermaidParser chịu trách nhiệm xây dựng mô hình lớp từ mã nguồn Mermaid classDiagram.
+type MermaidParser struct {
+	featureExtractor      *FeatureExtractor
+	relationshipExtractor *RelationshipExtractor
+}
+
+// NewMermaidParser creates a new instance of MermaidParser that reports problems to diagnostics.
+// NewMermaidParser tạo một phiên bản mới của MermaidParser, báo cáo các vấn đề vào diagnostics.
+func NewMermaidParser(diagnostics *models.Diagnostics) *MermaidParser {
+	return &MermaidParser{
+		featureExtractor:      NewFeatureExtractor(diagnostics),
+		relationshipExtractor: NewRelationshipExtractor(diagnostics),
+	}
+}
+
+// ExtractMermaidBlocks returns the mermaid sources of a Markdown document (fenced ```mermaid blocks).
+// Content without fences (a plain .mmd file) is returned as a single block.
+// ExtractMermaidBlocks trả về các mã nguồn mermaid trong tài liệu Markdown (các khối ```mermaid).
+// Nội dung không có khối rào (tệp .mmd thuần) được trả về như một khối duy nhất.
+func ExtractMermaidBlocks(content string) []string {
+	matches := reMermaidFence.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return []string{content}
+	}
+
+	var blocks []string
+	for _, m := range matches {
+		blocks = append(blocks, m[1])
+	}
+	return blocks
+}
+
+// Parse builds class models from one or more classDiagram blocks. The map is keyed by class name.
+// Parse xây dựng các mô hình lớp từ một hoặc nhiều khối classDiagram. Map được đánh khóa theo tên lớp.
+func (mp *MermaidParser) Parse(sources ...string) (map[string]*models.ClassModel, error) {
+	classes := make(map[string]*models.ClassModel)
+	var order []string
+	members := make(map[string][]string)
+	var relations []mermaidRelation
+	foundDiagram := false
+
+	// getClass trả về lớp theo tên, tạo mới nếu chưa tồn tại
+	getClass := func(name string) *models.ClassModel {
+		cls, ok := classes[name]
+		if !ok {
+			cls = &models.ClassModel{ID: name, Name: utils.SanitizeName(name), RawName: name, Type: models.Class}
+			classes[name] = cls
+			order = append(order, name)
+		}
+		return cls
+	}
+
+	for _, source := range sources {
+		inDiagram := false
+		var current *models.ClassModel
+
+		for _, rawLine := range strings.Split(source, "\n") {
+			line := strings.TrimSpace(rawLine)
+			if line == "" || strings.HasPrefix(line, "%%") {
+				continue
+			}
+
+			if !inDiagram {
+				// Bỏ qua mọi thứ trước tiêu đề (front-matter, các loại biểu đồ khác)
+				if strings.HasPrefix(line, "classDiagram") {
+					inDiagram = true
+					foundDiagram = true
+				}
+				continue
+			}
+
+			// Inside a class body
+			// Bên trong thân lớp
+			if current != nil {
+				if line == "}" {
+					current = nil
+				} else if m := reMermaidAnnotation.FindStringSubmatch(line); m != nil {
+					applyStereotype(current, m[1])
+				} else {
+					members[current.ID] = append(members[current.ID], line)
+				}
+				continue
+			}
+
+			if m := reMermaidClass.FindStringSubmatch(line); m != nil {
+				cls := getClass(m[1])
+				if m[2] != "" {
+					// class Repository~T~
+					cls.TypeParameters = parseTypeParameterList(mermaidGenerics(strings.Trim(m[2], "~")))
+				}
+				if m[3] == "{" && m[4] == "" {
+					current = cls
+				}
+				continue
+			}
+			if m := reMermaidAnnotation.FindStringSubmatch(line); m != nil {
+				if m[2] != "" {
+					applyStereotype(getClass(m[2]), m[1])
+				}
+				continue
+			}
+			if m := reMermaidRelation.FindStringSubmatch(line); m != nil {
+				getClass(m[1])
+				getClass(m[5])
+				relations = append(relations, mermaidRelation{
+					left: m[1], leftCard: m[2], arrow: m[3], rightCard: m[4], right: m[5], label: strings.TrimSpace(m[6]),
+				})
+				continue
+			}
+			if m := reMermaidMember.FindStringSubmatch(line); m != nil {
+				cls := getClass(m[1])
+				members[cls.ID] = append(members[cls.ID], m[2])
+				continue
+			}
+			// direction, note, click, style, classDef... không ảnh hưởng đến mã
+			utils.LogVerbose(fmt.Sprintf("Mermaid: skipped line %q", line))
+		}
+	}
+
+	if !foundDiagram {
+		return nil, fmt.Errorf("no mermaid classDiagram found (không tìm thấy classDiagram mermaid)")
+	}
+
+	// Members are added once the class types are known (interfaces force public abstract methods)
+	// Các thành viên được thêm sau khi đã biết loại lớp (giao diện buộc phương thức public abstract)
+	for i, name := range order {
+		cls := classes[name]
+		cls.Position = i
+		utils.LogVerbose(fmt.Sprintf("Found %s: %s", cls.Type, cls.Name))
+		for _, member := range members[name] {
+			mp.featureExtractor.addMember(cls, cls.ID, mp.convertMember(member), "", nil)
+		}
+	}
+
+	for _, rel := range relations {
+		mp.applyRelation(classes[rel.left], classes[rel.right], rel)
+	}
+
+	return classes, nil
+}
+
+// applyRelation turns a mermaid arrow into extends/implements or an association field.
+// applyRelation chuyển một mũi tên mermaid thành kế thừa/triển khai hoặc một trường liên kết.
+func (mp *MermaidParser) applyRelation(left, right *models.ClassModel, rel mermaidRelation) {
+	role := ""
+	if reIdentifier.MatchString(rel.label) {
+		role = rel.label
+	}
+
+	// Quan hệ theo chiều viết: bên trái là nguồn, bên phải là đích
+	edge := models.Relationship{
+		Label:     rel.label,
+		SourceEnd: models.RelationshipEnd{Multiplicity: rel.leftCard, Navigable: strings.HasPrefix(rel.arrow, "<")},
+		TargetEnd: models.RelationshipEnd{Multiplicity: rel.rightCard, Navigable: strings.HasSuffix(rel.arrow, ">")},
+	}
+	// Mũi tên ngược (<|--): bên phải là lớp con nên đổi chiều quan hệ
+	reversed := models.Relationship{Label: rel.label, SourceEnd: edge.TargetEnd, TargetEnd: edge.SourceEnd}
+
+	switch rel.arrow {
+	case "<|--":
+		mp.relationshipExtractor.link(right, left, reversed, true, false)
+	case "--|>":
+		mp.relationshipExtractor.link(left, right, edge, true, false)
+	case "<|..":
+		mp.relationshipExtractor.link(right, left, reversed, false, true)
+	case "..|>":
+		mp.relationshipExtractor.link(left, right, edge, false, true)
+	case "*--", "o--", "-->":
+		// Bên trái sở hữu bên phải
+		edge.Kind = mermaidKind(rel.arrow)
+		mp.relationshipExtractor.record(left, right, edge)
+		mp.relationshipExtractor.addAssociationField(left, right, rel.rightCard, role)
+	case "--*", "--o", "<--":
+		// Bên phải sở hữu bên trái
+		edge.Kind = mermaidKind(rel.arrow)
+		mp.relationshipExtractor.record(left, right, edge)
+		mp.relationshipExtractor.addAssociationField(right, left, rel.leftCard, role)
+	default:
+		// "--", "..", "..>", "<..": liên kết hoặc phụ thuộc, không sinh trường
+		edge.Kind = mermaidKind(rel.arrow)
+		mp.relationshipExtractor.record(left, right, edge)
+		utils.LogVerbose(fmt.Sprintf("Relationship: %s %s %s (no code generated)", left.Name, rel.arrow, right.Name))
+	}
+}
+
+// mermaidKind returns the relationship kind of a non-inheritance mermaid arrow.
+// mermaidKind trả về loại quan hệ của một mũi tên mermaid không phải kế thừa.
+func mermaidKind(arrow string) models.RelationshipKind {
+	switch {
+	case strings.Contains(arrow, "*"):
+		return models.Composition
+	case strings.Contains(arrow, "o"):
+		return models.Aggregation
+	case strings.Contains(arrow, ".."):
+		return models.Dependency
+	}
+	return models.Association
+}
+
+// convertMember rewrites a mermaid member ("+String name", "+area()$ double") into the
+// draw.io notation understood by FeatureExtractor ("+ static name: String", "+ area(): double").
+// convertMember chuyển một thành viên mermaid ("+String name", "+area()$ double") sang
+// ký pháp draw.io mà FeatureExtractor hiểu được ("+ static name: String", "+ area(): double").
+func (mp *MermaidParser) convertMember(member string) string {
+	member = strings.TrimSpace(member)
+	visibility := ""
+	if member != "" && strings.ContainsRune("+-#~", rune(member[0])) {
+		if member[0] != '~' {
+			visibility = member[:1] + " "
+		}
+		member = strings.TrimSpace(member[1:])
+	}
+
+	open := strings.Index(member, "(")
+	close := strings.LastIndex(member, ")")
+	if open != -1 && close > open {
+		// Method: name(params)[classifier] [ReturnType]
+		// Phương thức: tên(tham số)[bộ phân loại] [KiểuTrảVề]
+		name := strings.TrimSpace(member[:open])
+		after := strings.TrimSpace(member[close+1:])
+		modifiers := ""
+		for len(after) > 0 && (after[0] == '$' || after[0] == '*') {
+			if after[0] == '$' {
+				modifiers += "static "
+			} else {
+				modifiers += "abstract "
+			}
+			after = strings.TrimSpace(after[1:])
+		}
+
+		var params []string
+		for _, p := range utils.SplitTopLevel(mermaidGenerics(member[open+1:close]), ',') {
+			if p = strings.TrimSpace(p); p != "" {
+				params = append(params, mermaidParam(p))
+			}
+		}
+
+		converted := fmt.Sprintf("%s%s%s(%s)", visibility, modifiers, name, strings.Join(params, ", "))
+		if after != "" {
+			converted += ": " + mermaidGenerics(strings.TrimSpace(strings.TrimPrefix(after, ":")))
+		}
+		return strings.TrimSpace(converted)
+	}
+
+	// Field: "Type name", "name: Type" or a bare enum constant
+	// Trường: "Kiểu tên", "tên: Kiểu" hoặc hằng số enum đơn lẻ
+	modifiers := ""
+	if strings.HasSuffix(member, "$") {
+		modifiers = "static "
+		member = strings.TrimSpace(strings.TrimSuffix(member, "$"))
+	}
+	member = mermaidGenerics(member)
+
+	if strings.Contains(member, ":") {
+		return visibility + modifiers + member
+	}
+	tokens := strings.Fields(member)
+	if len(tokens) >= 2 {
+		name := tokens[len(tokens)-1]
+		return fmt.Sprintf("%s%s%s: %s", visibility, modifiers, name, strings.Join(tokens[:len(tokens)-1], " "))
+	}
+	return visibility + modifiers + member
+}
+
+// mermaidParam normalizes a parameter into "name: Type" (Object when the type is missing).
+// mermaidParam chuẩn hóa một tham số thành "tên: Kiểu" (Object khi thiếu kiểu).
+func mermaidParam(p string) string {
+	if !strings.ContainsAny(p, ": ") {
+		return p + ": Object"
+	}
+	return javaStyleParam(p)
+}
+
+// mermaidGenerics converts mermaid generics (List~String~, Map~K, List~V~~) to Java syntax (List<String>).
+// A tilde between an identifier and the next name opens a type argument list, any other tilde closes one.
+// mermaidGenerics chuyển generic của mermaid (List~String~, Map~K, List~V~~) sang cú pháp Java (List<String>).
+// Dấu ~ nằm giữa một định danh và tên tiếp theo sẽ mở danh sách đối số kiểu, các dấu ~ khác sẽ đóng lại.
+func mermaidGenerics(s string) string {
+	if !strings.Contains(s, "~") {
+		return s
+	}
+	runes := []rune(s)
+	var sb strings.Builder
+	isIdent := func(r rune) bool {
+		return r == '_' || r == '$' || r == '?' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+	}
+	for i, r := range runes {
+		if r != '~' {
+			sb.WriteRune(r)
+			continue
+		}
+		if i > 0 && i+1 < len(runes) && isIdent(runes[i-1]) && isIdent(runes[i+1]) {
+			sb.WriteRune('<')
+		} else {
+			sb.WriteRune('>')
+		}
+	}
+	return sb.String()
+}
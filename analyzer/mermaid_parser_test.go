@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"testing"
+)
+
+func TestMermaidConvertMember(t *testing.T) {
+	tests := []struct {
+		member string
+		want   string
+	}{
+		{member: "+String name", want: "+ name: String"},
+		{member: "-List~Item~ items", want: "- items: List<Item>"},
+		{member: "#count: int", want: "# count: int"},
+		{member: "+instance$", want: "+ static instance"},
+		{member: "RED", want: "RED"},
+		{member: "~helper()", want: "helper()"},
+		{member: "+area()$ double", want: "+ static area(): double"},
+		{member: "+draw()*", want: "+ abstract draw()"},
+		{member: "+put(Map~String, List~Integer~~ m, k) bool", want: "+ put(m: Map<String, List<Integer>>, k: Object): bool"},
+		{member: "+find(id: long) : Optional~User~", want: "+ find(id: long): Optional<User>"},
+	}
+
+	mp := NewMermaidParser(models.NewDiagnostics())
+	for _, tt := range tests {
+		t.Run(tt.member, func(t *testing.T) {
+			if got := mp.convertMember(tt.member); got != tt.want {
+				t.Errorf("convertMember(%q) = %q, want %q", tt.member, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractMermaidBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "plain mmd file", content: "classDiagram\nclass A\n", want: []string{"classDiagram\nclass A\n"}},
+		{
+			name:    "markdown with two fences",
+			content: "# Model\n```mermaid\nclassDiagram\nclass A\n```\ntext\n``` mermaid title\nclassDiagram\nclass B\n```\n",
+			want:    []string{"classDiagram\nclass A\n", "classDiagram\nclass B\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractMermaidBlocks(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractMermaidBlocks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMermaidParserParse(t *testing.T) {
+	source := `classDiagram
+    %% comment
+    class Shape {
+        <<abstract>>
+        -String name
+        +area()* double
+    }
+    class Drawable {
+        <<interface>>
+        +draw() void
+    }
+    class Repository~T~
+    Shape <|-- Circle
+    Circle ..|> Drawable
+    Order "1" *-- "*" Line : lines
+    Circle : +double radius
+`
+	classes, err := NewMermaidParser(models.NewDiagnostics()).Parse(source)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		kind       models.ClassType
+		extends    string
+		implements []string
+		fields     []string // "tên: Kiểu"
+		position   int
+	}{
+		{name: "Shape", kind: models.Abstract, fields: []string{"name: String"}, position: 0},
+		{name: "Drawable", kind: models.Interface, position: 1},
+		{name: "Repository", kind: models.Class, position: 2},
+		{name: "Circle", kind: models.Class, extends: "Shape", implements: []string{"Drawable"}, fields: []string{"radius: double"}, position: 3},
+		{name: "Order", kind: models.Class, fields: []string{"lines: List<Line>"}, position: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cls, ok := classes[tt.name]
+			if !ok {
+				t.Fatalf("class %s not found", tt.name)
+			}
+			if cls.Type != tt.kind || cls.Extends() != tt.extends || !reflect.DeepEqual(cls.Implements(), tt.implements) || cls.Position != tt.position {
+				t.Errorf("%s = %s extends %q implements %v at %d; want %s extends %q implements %v at %d",
+					tt.name, cls.Type, cls.Extends(), cls.Implements(), cls.Position, tt.kind, tt.extends, tt.implements, tt.position)
+			}
+			var fields []string
+			for _, f := range cls.Fields {
+				fields = append(fields, f.Name+": "+f.Type)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("fields of %s = %v, want %v", tt.name, fields, tt.fields)
+			}
+		})
+	}
+
+	if got := classes["Repository"].TypeParameters; len(got) != 1 || got[0].Name != "T" {
+		t.Errorf("type parameters of Repository = %+v, want [T]", got)
+	}
+}
+
+func TestMermaidParserWithoutDiagram(t *testing.T) {
+	if _, err := NewMermaidParser(models.NewDiagnostics()).Parse("graph TD\nA --> B\n"); err == nil {
+		t.Errorf("Parse accepted a flowchart")
+	}
+}
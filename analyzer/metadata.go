@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"fmt"
+	"nUML/models"
+	"nUML/utils"
+	"regexp"
+	"strings"
+)
+
+// reWord matches a word of a class label, used to look for misspelled keywords.
+// reWord khớp một từ của nhãn lớp, dùng để tìm các từ khóa viết sai.
+var reWord = regexp.MustCompile(`[A-Za-z]+`)
+
+// reStereotype matches a stereotype such as <<interface>>, «Entity» or <<runtime exception>>.
+// reStereotype khớp một khuôn mẫu như <<interface>>, «Entity» hoặc <<runtime exception>>.
+var reStereotype = regexp.MustCompile(`(<<|«)\s*([\w ]+?)\s*(>>|»)`)
+
+// parseAnnotations reads the "annotations" property of a shape into a list of Java annotations.
+// Entries may be separated by commas, semicolons or new lines, e.g. "@Entity, @Table(name = \"users\")".
+// parseAnnotations đọc thuộc tính "annotations" của một hình thành danh sách các chú thích Java.
+// Các mục có thể phân tách bằng dấu phẩy, dấu chấm phẩy hoặc xuống dòng, ví dụ "@Entity, @Table(name = \"users\")".
+func parseAnnotations(props map[string]string) []string {
+	raw, ok := props["annotations"]
+	if !ok {
+		return nil
+	}
+
+	var annotations []string
+	raw = strings.NewReplacer("\n", ";", "\r", "").Replace(raw)
+	for _, group := range utils.SplitTopLevel(raw, ';') {
+		for _, a := range utils.SplitTopLevel(group, ',') {
+			a = strings.TrimSpace(a)
+			if a == "" {
+				continue
+			}
+			if !strings.HasPrefix(a, "@") {
+				a = "@" + a
+			}
+			annotations = append(annotations, a)
+		}
+	}
+	return annotations
+}
+
+// applyStereotype maps a textual stereotype (<<interface>>, <<enumeration>>, <<abstract>>, <<record>>) onto the class type.
+// Unknown stereotypes leave the type unchanged.
+// applyStereotype ánh xạ một khuôn mẫu dạng văn bản (<<interface>>, <<enumeration>>, <<abstract>>, <<record>>) sang loại lớp.
+// Các khuôn mẫu không xác định sẽ giữ nguyên loại.
+func applyStereotype(cls *models.ClassModel, stereotype string) {
+	if cls.Stereotype == "" {
+		cls.Stereotype = strings.TrimSpace(stereotype)
+	}
+	switch tag := strings.ToLower(strings.TrimSpace(stereotype)); {
+	case tag == "interface" || utils.IsFuzzyMatch(tag, "interface"):
+		cls.Type = models.Interface
+	case tag == "enumeration" || tag == "enum" || utils.IsFuzzyMatch(tag, "enum"):
+		cls.Type = models.Enum
+	case tag == "abstract":
+		cls.Type = models.Abstract
+	case tag == "record":
+		cls.Type = models.Record
+	}
+}
+
+// parseStereotype returns the first stereotype written in a class label, without the guillemets.
+// parseStereotype trả về khuôn mẫu đầu tiên được ghi trong nhãn lớp, không kèm dấu ngoặc kép góc.
+func parseStereotype(raw string) string {
+	if m := reStereotype.FindStringSubmatch(utils.CleanHTML(raw)); m != nil {
+		return m[2]
+	}
+	return ""
+}
+
+// reportStereotypeTypo reports a misspelled keyword in the header of a class (<<Iterface>>, <<Emun>>) that was
+// accepted through utils.IsFuzzyMatch, with a fix spelling it out in the label of the cell.
+// reportStereotypeTypo báo cáo một từ khóa viết sai trong tiêu đề lớp (<<Iterface>>, <<Emun>>) đã được
+// chấp nhận qua utils.IsFuzzyMatch, kèm chỉnh sửa viết đúng nó trong nhãn của ô.
+func reportStereotypeTypo(diagnostics *models.Diagnostics, cell models.MxCell, header, className string) {
+	for _, word := range reWord.FindAllString(utils.CleanHTML(header), -1) {
+		for _, keyword := range []string{"interface", "enum", "record"} {
+			if strings.EqualFold(word, keyword) || !utils.IsFuzzyMatch(word, keyword) {
+				continue
+			}
+			message := fmt.Sprintf("%q in the label of %s is read as %s (từ khóa viết sai)", word, className, keyword)
+			// Nhãn dùng chỗ giữ chỗ %tên% không thể ghi lại từ giá trị đã thay thế
+			if cell.Properties["placeholders"] == "1" || !strings.Contains(cell.Value, word) {
+				diagnostics.Report(models.SeverityWarning, models.RuleStereotypeTypo, cell.ID, cell.Page, message)
+				return
+			}
+			diagnostics.ReportFix(models.SeverityWarning, models.RuleStereotypeTypo, cell.ID, cell.Page, message,
+				models.Fix{Page: cell.Page, CellID: cell.ID, Attribute: "value", Value: strings.Replace(cell.Value, word, keyword, 1)})
+			return
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"fmt"
+	"nUML/models"
+	"nUML/utils"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// rePackageName matches a dotted Java package name such as com.acme.billing.
+	// rePackageName khớp tên gói Java có dấu chấm như com.acme.billing.
+	rePackageName = regexp.MustCompile(`^[a-zA-Z_][\w]*(\.[a-zA-Z_][\w]*)*$`)
+	// rePackageKeyword matches a leading "package" keyword or <<package>> stereotype in a container label.
+	// rePackageKeyword khớp từ khóa "package" hoặc khuôn mẫu <<package>> ở đầu nhãn của khung chứa.
+	rePackageKeyword = regexp.MustCompile(`(?i)^((<<|«)\s*package\s*(>>|»)|package\b)\s*`)
+)
+
+// PackageExtractor is responsible for assigning Java packages from the containers (package frames) around classes.
+// PackageExtractor chịu trách nhiệm gán gói Java từ các khung chứa (khung gói) bao quanh các lớp.
+type PackageExtractor struct{}
+
+// NewPackageExtractor creates a new instance of PackageExtractor.
+// NewPackageExtractor tạo một phiên bản mới của PackageExtractor.
+func NewPackageExtractor() *PackageExtractor {
+	return &PackageExtractor{}
+}
+
+// Extract sets ClassModel.Package by walking up the parent chain of each class cell.
+// Nested package frames are joined (com.acme > billing = com.acme.billing); a "package" shape property wins.
+// Extract gán ClassModel.Package bằng cách đi ngược chuỗi cha của mỗi ô lớp.
+// Các khung gói lồng nhau được nối lại (com.acme > billing = com.acme.billing); thuộc tính "package" của hình được ưu tiên.
+func (pe *PackageExtractor) Extract(cells []models.MxCell, classes map[string]*models.ClassModel) {
+	byID := make(map[string]models.MxCell)
+	for _, cell := range cells {
+		byID[cell.ID] = cell
+	}
+
+	// Duyệt theo ID để log verbose giống nhau ở mọi lần chạy
+	var ids []string
+	for id := range classes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		cls := classes[id]
+		if pkg := strings.TrimSpace(cls.Properties["package"]); pkg != "" {
+			cls.Package = pkg
+			continue
+		}
+
+		// Thu thập nhãn của các khung gói từ trong ra ngoài
+		var names []string
+		visited := make(map[string]bool)
+		cell, ok := byID[id]
+		for ok && cell.Parent != "" && !visited[cell.Parent] {
+			visited[cell.Parent] = true
+			cell, ok = byID[cell.Parent]
+			if ok && isPackageContainer(cell) {
+				if name := packageLabel(cell); name != "" {
+					names = append(names, name)
+				}
+			}
+		}
+
+		// Nối từ ngoài vào trong; nhãn đã đầy đủ (com.acme.billing trong com.acme) được giữ nguyên
+		pkg := ""
+		for i := len(names) - 1; i >= 0; i-- {
+			switch {
+			case pkg == "":
+				pkg = names[i]
+			case strings.HasPrefix(names[i], pkg+"."):
+				pkg = names[i]
+			default:
+				pkg = pkg + "." + names[i]
+			}
+		}
+		if pkg != "" {
+			cls.Package = pkg
+			utils.LogVerbose(fmt.Sprintf("Package: %s in %s", cls.Name, pkg))
+		}
+	}
+}
+
+// isPackageContainer reports whether a cell is a package frame: a folder/package/frame shape,
+// a style flagged with "package", or a plain container whose label is a package name.
+// isPackageContainer cho biết một ô có phải là khung gói không: hình folder/package/frame,
+// kiểu được đánh dấu "package", hoặc một khung chứa thông thường có nhãn là tên gói.
+func isPackageContainer(cell models.MxCell) bool {
+	if cell.Vertex != "1" {
+		return false
+	}
+	for _, token := range strings.Split(cell.Style, ";") {
+		token = strings.TrimSpace(token)
+		switch {
+		case token == "shape=folder", token == "shape=package", token == "shape=umlFrame":
+			return true
+		case token == "package", token == "package=1":
+			return true
+		}
+	}
+	if strings.Contains(cell.Style, "container=1") && !strings.Contains(cell.Style, "swimlane") {
+		return rePackageName.MatchString(packageLabel(cell)) && strings.Contains(packageLabel(cell), ".")
+	}
+	return false
+}
+
+// packageLabel returns the package name written on a container ("package com.acme" -> "com.acme").
+// packageLabel trả về tên gói được ghi trên khung chứa ("package com.acme" -> "com.acme").
+func packageLabel(cell models.MxCell) string {
+	label := utils.CleanHTML(strings.ReplaceAll(cell.Value, "\n", " "))
+	label = strings.TrimSpace(rePackageKeyword.ReplaceAllString(label, ""))
+	if !rePackageName.MatchString(label) {
+		// Nhãn tự do (ví dụ "Billing Module") được chuyển thành một đoạn gói hợp lệ
+		label = strings.ToLower(utils.SanitizeName(label))
+	}
+	return label
+}
@@ -0,0 +1,54 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"strings"
+	"testing"
+)
+
+func TestPackageExtractorExtract(t *testing.T) {
+	cells := []models.MxCell{
+		packageCell("acme", "", "com.acme"),
+		packageCell("billing", "acme", "billing"),
+		classCell("invoice", "billing", "Invoice"),
+		// Nhãn đã đầy đủ bên trong khung cha được giữ nguyên
+		packageCell("shipping", "acme", "com.acme.shipping"),
+		classCell("parcel", "shipping", "Parcel"),
+		{ID: "shop", Value: "&lt;&lt;package&gt;&gt; com.shop", Style: "swimlane;package=1;", Vertex: "1"},
+		classCell("cart", "shop", "Cart"),
+		packageCell("module", "", "Billing Module"),
+		classCell("tax", "module", "Tax"),
+		{ID: "group", Value: "Helpers", Style: "container=1;", Vertex: "1"},
+		classCell("util", "group", "Util"),
+		{ID: "dotted", Value: "org.example", Style: "rounded=0;container=1;", Vertex: "1"},
+		classCell("app", "dotted", "App"),
+		classCell("user", "acme", "User"),
+		classCell("root", "", "Main"),
+	}
+	classes := make(map[string]*models.ClassModel)
+	for _, cell := range cells {
+		if strings.Contains(cell.Style, "swimlane") && !isPackageContainer(cell) {
+			classes[cell.ID] = &models.ClassModel{ID: cell.ID, Name: cell.Value}
+		}
+	}
+	// Thuộc tính "package" của hình được ưu tiên hơn khung bao quanh
+	classes["user"].Properties = map[string]string{"package": "com.acme.users"}
+
+	NewPackageExtractor().Extract(cells, classes)
+
+	want := map[string]string{
+		"invoice": "com.acme.billing",
+		"parcel":  "com.acme.shipping",
+		"cart":    "com.shop",
+		"tax":     "billingmodule",
+		"util":    "",
+		"app":     "org.example",
+		"user":    "com.acme.users",
+		"root":    "",
+	}
+	for id, pkg := range want {
+		if got := classes[id].Package; got != pkg {
+			t.Errorf("package of %s = %q, want %q", classes[id].Name, got, pkg)
+		}
+	}
+}
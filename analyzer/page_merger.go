@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"fmt"
+	"nUML/models"
+	"nUML/utils"
+	"sort"
+)
+
+// PageMerger is responsible for joining classes that appear on several pages under the same name.
+// PageMerger chịu trách nhiệm hợp nhất các lớp xuất hiện trên nhiều trang với cùng một tên.
+type PageMerger struct{}
+
+// NewPageMerger creates a new instance of PageMerger.
+// NewPageMerger tạo một phiên bản mới của PageMerger.
+func NewPageMerger() *PageMerger {
+	return &PageMerger{}
+}
+
+// Merge links the classes of several pages that share a package and a name to a single canonical model.
+// A class without a package (a "reference" box drawn outside the package frame) joins the class of that
+// name when only one package has it. Each per-page map is rewritten so its IDs point to the canonical
+// model, which lets edges drawn on one page resolve against a class defined on another. The returned map
+// holds each class once.
+// Merge liên kết các lớp cùng gói và cùng tên giữa các trang vào một mô hình chuẩn duy nhất. Một lớp
+// không có gói (hộp "tham chiếu" vẽ ngoài khung gói) được gộp vào lớp cùng tên khi chỉ một gói có lớp đó.
+// Mỗi map theo trang được ghi lại để ID trỏ tới mô hình chuẩn, giúp các cạnh vẽ trên một trang được giải
+// quyết với lớp định nghĩa ở trang khác. Map trả về chứa mỗi lớp đúng một lần.
+func (pm *PageMerger) Merge(pages []models.Diagram, perPage []map[string]*models.ClassModel) map[string]*models.ClassModel {
+	merged := make(map[string]*models.ClassModel)
+	byName := make(map[string][]*models.ClassModel) // Tên -> các lớp chuẩn, mỗi gói một lớp
+
+	for i, classes := range perPage {
+		// Duyệt ID theo thứ tự để lớp chuẩn được chọn một cách ổn định
+		var ids []string
+		for id := range classes {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+
+		for _, id := range ids {
+			cls := classes[id]
+			// Các lớp trùng tên trong cùng một trang được giữ nguyên như trước
+			canonical := pm.find(byName[cls.Name], cls)
+			if canonical != nil && canonical.Page != cls.Page {
+				pm.mergeInto(canonical, cls)
+				classes[id] = canonical
+				utils.LogVerbose(fmt.Sprintf("Merged %s from page %s into page %s", cls.Name, cls.Page, canonical.Page))
+				continue
+			}
+
+			if canonical == nil {
+				byName[cls.Name] = append(byName[cls.Name], cls)
+			}
+			merged[pages[i].Name+"/"+id] = cls
+		}
+	}
+	return merged
+}
+
+// find returns the canonical class cls belongs to: the one with the same package, or the only class of
+// that name when one of the two has no package. It returns nil when there is none or it is ambiguous.
+// find trả về lớp chuẩn mà cls thuộc về: lớp cùng gói, hoặc lớp duy nhất có tên đó khi một trong hai
+// không có gói. Trả về nil khi không có hoặc không rõ ràng.
+func (pm *PageMerger) find(candidates []*models.ClassModel, cls *models.ClassModel) *models.ClassModel {
+	for _, c := range candidates {
+		if c.Package == cls.Package {
+			return c
+		}
+	}
+	if len(candidates) == 1 && (cls.Package == "" || candidates[0].Package == "") {
+		return candidates[0]
+	}
+	return nil
+}
+
+// mergeInto copies the features of dup that the canonical class does not have yet.
+// mergeInto sao chép các đặc điểm của dup mà lớp chuẩn chưa có.
+func (pm *PageMerger) mergeInto(canonical, dup *models.ClassModel) {
+	// Hộp "tham chiếu" thường được vẽ như một lớp trống, nên ưu tiên loại cụ thể hơn
+	if canonical.Type == models.Class && dup.Type != models.Class {
+		canonical.Type = dup.Type
+	}
+	if canonical.Package == "" {
+		canonical.Package = dup.Package
+	}
+
+	for _, f := range dup.Fields {
+		hasIt := false
+		for _, cf := range canonical.Fields {
+			if cf.Name == f.Name {
+				hasIt = true
+				break
+			}
+		}
+		if !hasIt {
+			canonical.Fields = append(canonical.Fields, f)
+		}
+	}
+
+	for _, m := range dup.Methods {
+		hasIt := false
+		for _, cm := range canonical.Methods {
+			if cm.Signature() == m.Signature() {
+				hasIt = true
+				break
+			}
+		}
+		if !hasIt {
+			canonical.Methods = append(canonical.Methods, m)
+		}
+	}
+}
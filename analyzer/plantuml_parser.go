@@ -0,0 +1,494 @@
+package analyzer
+
+import (
+	"fmt"
+	"nUML/models"
+	"nUML/utils"
+	"regexp"
+	"strings"
+)
+
+var (
+	// rePlantUMLBlock matches an @startuml ... @enduml block.
+	// rePlantUMLBlock khớp một khối @startuml ... @enduml.
+	rePlantUMLBlock = regexp.MustCompile(`(?s)@startuml[^\n]*\n(.*?)@enduml`)
+	// rePlantUMLDecl matches the start of a class-like declaration ("abstract class Foo", "interface Bar" ...).
+	// rePlantUMLDecl khớp phần đầu của một khai báo giống lớp ("abstract class Foo", "interface Bar" ...).
+	rePlantUMLDecl = regexp.MustCompile(`^(abstract\s+class|abstract|class|interface|enum|record|entity|exception|annotation)\s+(.*)$`)
+	// rePlantUMLPackage matches "package com.acme {" or "namespace com.acme {".
+	// rePlantUMLPackage khớp "package com.acme {" hoặc "namespace com.acme {".
+	rePlantUMLPackage = regexp.MustCompile(`^(package|namespace)\s+"?([\w.$]+)"?[^{]*\{\s*$`)
+	// rePlantUMLRelation matches `A "1" *-- "many" B : label >`, including direction hints such as -up-|>.
+	// rePlantUMLRelation khớp `A "1" *-- "many" B : nhãn >`, bao gồm gợi ý hướng như -up-|>.
+	rePlantUMLRelation = regexp.MustCompile(`^([\w.$]+)\s*(?:"([^"]*)")?\s*(<\||\^|\*|o|<|#|x|\+)?((?:-+|\.+)(?:(?:up|down|left|right|u|d|l|r|\[[^\]]*\])(?:-+|\.+))?)(\|>|\^|\*|o|>|#|x|\+)?\s*(?:"([^"]*)")?\s*([\w.$]+)\s*(?::\s*(.*))?$`)
+	// rePlantUMLMember matches "Name : member".
+	// rePlantUMLMember khớp "Name : thành_viên".
+	rePlantUMLMember = regexp.MustCompile(`^([\w.$]+)\s*:\s*(.+)$`)
+	// rePlantUMLSeparator matches body separators ("--", "..", "==", "__" optionally with a title).
+	// rePlantUMLSeparator khớp các dấu phân cách trong thân ("--", "..", "==", "__" có thể kèm tiêu đề).
+	rePlantUMLSeparator = regexp.MustCompile(`^(--|\.\.|==|__)`)
+	// rePlantUMLStereotype matches <<stereotype>>.
+	// rePlantUMLStereotype khớp <<khuôn mẫu>>.
+	rePlantUMLStereotype = regexp.MustCompile(`<<\s*([^>]+?)\s*>>`)
+)
+
+// plantUMLRelation is a relationship line collected while parsing, applied once all classes are known.
+// plantUMLRelation là một dòng quan hệ được thu thập khi phân tích, áp dụng sau khi đã biết tất cả các lớp.
+type plantUMLRelation struct {
+	left, right         string
+	leftCard, rightCard string
+	leftEnd, rightEnd   string
+	arrow               string
+	typeArgs            []string
+	dashed              bool
+	label               string
+}
+
+// PlantUMLParser is responsible for building class models from PlantUML class diagrams.
+// PlantUMLParser chịu trách nhiệm xây dựng mô hình lớp từ biểu đồ lớp PlantUML.
+type PlantUMLParser struct {
+	featureExtractor      *FeatureExtractor
+	relationshipExtractor *RelationshipExtractor
+}
+
+// NewPlantUMLParser creates a new instance of PlantUMLParser that reports problems to diagnostics.
+// NewPlantUMLParser tạo một phiên bản mới của PlantUMLParser, báo cáo các vấn đề vào diagnostics.
+func NewPlantUMLParser(diagnostics *models.Diagnostics) *PlantUMLParser {
+	return &PlantUMLParser{
+		featureExtractor:      NewFeatureExtractor(diagnostics),
+		relationshipExtractor: NewRelationshipExtractor(diagnostics),
+	}
+}
+
+// ExtractPlantUMLBlocks returns the content of every @startuml ... @enduml block.
+// Content without markers is returned as a single block.
+// ExtractPlantUMLBlocks trả về nội dung của mọi khối @startuml ... @enduml.
+// Nội dung không có dấu mốc được trả về như một khối duy nhất.
+func ExtractPlantUMLBlocks(content string) []string {
+	matches := rePlantUMLBlock.FindAllStringSubmatch(content, -1)
+	if len(matches) == 0 {
+		return []string{content}
+	}
+
+	var blocks []string
+	for _, m := range matches {
+		blocks = append(blocks, m[1])
+	}
+	return blocks
+}
+
+// Parse builds class models from one or more PlantUML blocks. The map is keyed by class name.
+// Parse xây dựng các mô hình lớp từ một hoặc nhiều khối PlantUML. Map được đánh khóa theo tên lớp.
+func (pp *PlantUMLParser) Parse(sources ...string) (map[string]*models.ClassModel, error) {
+	classes := make(map[string]*models.ClassModel)
+	var order []string
+	members := make(map[string][]string)
+	var relations []plantUMLRelation
+
+	// getClass trả về lớp theo tên (có thể là tên đầy đủ com.acme.Foo), tạo mới nếu chưa tồn tại
+	getClass := func(name, pkg string) *models.ClassModel {
+		if idx := strings.LastIndex(name, "."); idx != -1 {
+			pkg = name[:idx]
+			name = name[idx+1:]
+		}
+		cls, ok := classes[name]
+		if !ok {
+			cls = &models.ClassModel{ID: name, Name: utils.SanitizeName(name), RawName: name, Type: models.Class, Package: pkg}
+			classes[name] = cls
+			order = append(order, name)
+		} else if cls.Package == "" {
+			cls.Package = pkg
+		}
+		return cls
+	}
+
+	for _, source := range sources {
+		// Ngăn xếp các khối lồng nhau: tên gói, hoặc "" cho các khối khác (together {...})
+		var scopes []string
+		var current *models.ClassModel
+		inNote := false
+
+		currentPackage := func() string {
+			var parts []string
+			for _, s := range scopes {
+				if s != "" {
+					parts = append(parts, s)
+				}
+			}
+			return strings.Join(parts, ".")
+		}
+
+		for _, rawLine := range strings.Split(source, "\n") {
+			line := strings.TrimSpace(rawLine)
+			if line == "" || strings.HasPrefix(line, "'") {
+				continue
+			}
+
+			// Multi-line notes and legends carry no model information
+			// Ghi chú và chú giải nhiều dòng không mang thông tin mô hình
+			if inNote {
+				if strings.HasPrefix(line, "end note") || strings.HasPrefix(line, "endnote") || strings.HasPrefix(line, "end legend") || strings.HasPrefix(line, "endlegend") {
+					inNote = false
+				}
+				continue
+			}
+			if (strings.HasPrefix(line, "note ") && !strings.Contains(line, ":") && !strings.Contains(line, "\"")) || line == "legend" || strings.HasPrefix(line, "legend ") {
+				inNote = true
+				continue
+			}
+
+			// Inside a class body
+			// Bên trong thân lớp
+			if current != nil {
+				switch {
+				case line == "}":
+					current = nil
+				case rePlantUMLSeparator.MatchString(line):
+					// Dấu phân cách giữa các ngăn
+				case current.Type == models.Enum && !strings.Contains(line, "(") && !strings.Contains(line, ":"):
+					// Hằng số enum có thể được liệt kê trên cùng một dòng: RED, GREEN
+					for _, c := range strings.Split(strings.TrimSuffix(line, ";"), ",") {
+						if c = strings.TrimSpace(c); c != "" {
+							members[current.ID] = append(members[current.ID], c)
+						}
+					}
+				default:
+					members[current.ID] = append(members[current.ID], line)
+				}
+				continue
+			}
+
+			if m := rePlantUMLPackage.FindStringSubmatch(line); m != nil {
+				scopes = append(scopes, m[2])
+				continue
+			}
+			if m := rePlantUMLDecl.FindStringSubmatch(line); m != nil {
+				cls, opensBody := pp.parseDeclaration(m[1], m[2], currentPackage(), getClass, &relations)
+				if opensBody {
+					current = cls
+				}
+				continue
+			}
+			if line == "}" {
+				if len(scopes) > 0 {
+					scopes = scopes[:len(scopes)-1]
+				}
+				continue
+			}
+			if strings.HasSuffix(line, "{") {
+				// together { ... } hoặc các khối nhóm khác
+				scopes = append(scopes, "")
+				continue
+			}
+			if m := rePlantUMLRelation.FindStringSubmatch(line); m != nil {
+				getClass(m[1], currentPackage())
+				getClass(m[7], currentPackage())
+				relations = append(relations, plantUMLRelation{
+					left: pp.shortName(m[1]), leftCard: m[2], leftEnd: m[3],
+					arrow: m[3] + m[4] + m[5], dashed: strings.Contains(m[4], "."),
+					rightEnd: m[5], rightCard: m[6], right: pp.shortName(m[7]),
+					label: strings.TrimSpace(strings.Trim(strings.TrimSpace(m[8]), "<>")),
+				})
+				continue
+			}
+			if m := rePlantUMLMember.FindStringSubmatch(line); m != nil {
+				cls := getClass(m[1], currentPackage())
+				members[cls.ID] = append(members[cls.ID], m[2])
+				continue
+			}
+			// skinparam, hide, show, title, left to right direction... không ảnh hưởng đến mã
+			utils.LogVerbose(fmt.Sprintf("PlantUML: skipped line %q", line))
+		}
+	}
+
+	if len(classes) == 0 {
+		return nil, fmt.Errorf("no PlantUML classes found (không tìm thấy lớp PlantUML nào)")
+	}
+
+	// Members are added once the class types are known (interfaces force public abstract methods)
+	// Các thành viên được thêm sau khi đã biết loại lớp (giao diện buộc phương thức public abstract)
+	for i, name := range order {
+		cls := classes[name]
+		cls.Position = i
+		utils.LogVerbose(fmt.Sprintf("Found %s: %s", cls.Type, cls.Name))
+		for _, member := range members[name] {
+			pp.featureExtractor.addMember(cls, cls.ID, pp.convertMember(member), "", nil)
+		}
+	}
+
+	for _, rel := range relations {
+		pp.applyRelation(classes[rel.left], classes[rel.right], rel)
+	}
+
+	return classes, nil
+}
+
+// parseDeclaration handles "class Foo<T> <<Entity>> extends Bar implements Baz {" and reports whether a body opens.
+// parseDeclaration xử lý "class Foo<T> <<Entity>> extends Bar implements Baz {" và cho biết có mở thân lớp hay không.
+func (pp *PlantUMLParser) parseDeclaration(keyword, rest, pkg string, getClass func(string, string) *models.ClassModel, relations *[]plantUMLRelation) (*models.ClassModel, bool) {
+	rest = strings.TrimSpace(rest)
+	opensBody := false
+	if strings.HasSuffix(rest, "{}") {
+		rest = strings.TrimSpace(strings.TrimSuffix(rest, "{}"))
+	} else if strings.HasSuffix(rest, "{") {
+		rest = strings.TrimSpace(strings.TrimSuffix(rest, "{"))
+		opensBody = true
+	}
+
+	// Stereotypes: <<Entity>>, <<interface>>
+	// Khuôn mẫu: <<Entity>>, <<interface>>
+	var stereotypes []string
+	for _, m := range rePlantUMLStereotype.FindAllStringSubmatch(rest, -1) {
+		stereotypes = append(stereotypes, m[1])
+	}
+	rest = rePlantUMLStereotype.ReplaceAllString(rest, " ")
+
+	// extends / implements clauses
+	// Mệnh đề extends / implements
+	var extends, implements []string
+	lower := strings.ToLower(rest)
+	if idx := strings.Index(lower, " implements "); idx != -1 {
+		implements = utils.SplitTopLevel(rest[idx+len(" implements "):], ',')
+		rest, lower = rest[:idx], lower[:idx]
+	}
+	if idx := strings.Index(lower, " extends "); idx != -1 {
+		extends = utils.SplitTopLevel(rest[idx+len(" extends "):], ',')
+		rest = rest[:idx]
+	}
+	rest = strings.TrimSpace(rest)
+
+	// Name: "Long Name" as Alias, Foo<T>, Point(int x, int y)
+	// Tên: "Tên dài" as Bí_danh, Foo<T>, Point(int x, int y)
+	var components string
+	if idx := strings.Index(rest, " as "); idx != -1 && strings.HasPrefix(rest, "\"") {
+		rest = strings.TrimSpace(rest[idx+4:])
+	}
+	rest = strings.Trim(rest, "\"")
+	if open := strings.Index(rest, "("); open != -1 && strings.HasSuffix(rest, ")") {
+		components = rest[open+1 : len(rest)-1]
+		rest = rest[:open]
+	}
+	name := rest
+	if idx := strings.IndexAny(name, "< "); idx != -1 {
+		name = name[:idx]
+	}
+
+	cls := getClass(name, pkg)
+	cls.RawName = strings.TrimSpace(rest)
+	if _, list := splitGenerics(rest); list != "" {
+		cls.TypeParameters = parseTypeParameterList(list)
+	}
+	switch strings.Fields(keyword)[0] {
+	case "class", "entity", "exception", "annotation":
+		cls.Type = models.Class
+	case "abstract":
+		cls.Type = models.Abstract
+	case "interface":
+		cls.Type = models.Interface
+	case "enum":
+		cls.Type = models.Enum
+	case "record":
+		cls.Type = models.Record
+	}
+	for _, s := range stereotypes {
+		applyStereotype(cls, s)
+	}
+	if strings.Fields(keyword)[0] == "exception" && cls.Stereotype == "" {
+		cls.Stereotype = "exception"
+	}
+
+	// Record components become fields
+	// Các thành phần của record trở thành trường
+	for _, c := range utils.SplitTopLevel(components, ',') {
+		if c = strings.TrimSpace(c); c != "" {
+			pp.featureExtractor.addMember(cls, cls.ID, javaStyleParam(c), "", nil)
+		}
+	}
+
+	for _, parent := range extends {
+		parent = strings.TrimSpace(parent)
+		getClass(pp.baseName(parent), pkg)
+		_, args := splitGenerics(parent)
+		*relations = append(*relations, plantUMLRelation{left: cls.ID, right: pp.shortName(pp.baseName(parent)), rightEnd: "|>", typeArgs: splitTypeArguments(args)})
+	}
+	for _, iface := range implements {
+		iface = strings.TrimSpace(iface)
+		// Kiểu chỉ xuất hiện trong mệnh đề implements được coi là giao diện (khai báo sau có thể ghi đè)
+		if target := getClass(pp.baseName(iface), pkg); target.Type == models.Class {
+			target.Type = models.Interface
+		}
+		_, args := splitGenerics(iface)
+		*relations = append(*relations, plantUMLRelation{left: cls.ID, right: pp.shortName(pp.baseName(iface)), rightEnd: "|>", dashed: true, typeArgs: splitTypeArguments(args)})
+	}
+
+	return cls, opensBody
+}
+
+// applyRelation turns a PlantUML arrow into extends/implements or an association field.
+// applyRelation chuyển một mũi tên PlantUML thành kế thừa/triển khai hoặc một trường liên kết.
+func (pp *PlantUMLParser) applyRelation(left, right *models.ClassModel, rel plantUMLRelation) {
+	role := ""
+	if reIdentifier.MatchString(rel.label) {
+		role = rel.label
+	}
+
+	// Quan hệ theo chiều viết: bên trái là nguồn, bên phải là đích
+	edge := models.Relationship{
+		Label:         rel.label,
+		TypeArguments: rel.typeArgs,
+		SourceEnd:     models.RelationshipEnd{Multiplicity: rel.leftCard, Navigable: rel.leftEnd == "<"},
+		TargetEnd:     models.RelationshipEnd{Multiplicity: rel.rightCard, Navigable: rel.rightEnd == ">"},
+	}
+	// Mũi tên ngược (<|--): bên phải là lớp con nên đổi chiều quan hệ
+	reversed := models.Relationship{Label: rel.label, TypeArguments: rel.typeArgs, SourceEnd: edge.TargetEnd, TargetEnd: edge.SourceEnd}
+
+	switch {
+	case rel.leftEnd == "<|" || rel.leftEnd == "^":
+		pp.relationshipExtractor.link(right, left, reversed, !rel.dashed, rel.dashed)
+	case rel.rightEnd == "|>" || rel.rightEnd == "^":
+		pp.relationshipExtractor.link(left, right, edge, !rel.dashed, rel.dashed)
+	case rel.leftEnd == "*" || rel.leftEnd == "o":
+		// Hình thoi ở bên trái: bên trái sở hữu bên phải
+		edge.Kind = plantUMLDiamondKind(rel.leftEnd)
+		pp.relationshipExtractor.record(left, right, edge)
+		pp.relationshipExtractor.addAssociationField(left, right, rel.rightCard, role)
+	case rel.rightEnd == "*" || rel.rightEnd == "o":
+		edge.Kind = plantUMLDiamondKind(rel.rightEnd)
+		pp.relationshipExtractor.record(left, right, edge)
+		pp.relationshipExtractor.addAssociationField(right, left, rel.leftCard, role)
+	case rel.rightEnd == ">" && !rel.dashed:
+		edge.Kind = models.Association
+		pp.relationshipExtractor.record(left, right, edge)
+		pp.relationshipExtractor.addAssociationField(left, right, rel.rightCard, role)
+	case rel.leftEnd == "<" && !rel.dashed:
+		edge.Kind = models.Association
+		pp.relationshipExtractor.record(left, right, edge)
+		pp.relationshipExtractor.addAssociationField(right, left, rel.leftCard, role)
+	default:
+		// Liên kết không hướng hoặc phụ thuộc (..>), không sinh trường
+		edge.Kind = models.Association
+		if rel.dashed {
+			edge.Kind = models.Dependency
+		}
+		pp.relationshipExtractor.record(left, right, edge)
+		utils.LogVerbose(fmt.Sprintf("Relationship: %s %s %s (no code generated)", left.Name, rel.arrow, right.Name))
+	}
+}
+
+// plantUMLDiamondKind returns composition for a "*" end and aggregation for an "o" end.
+// plantUMLDiamondKind trả về hợp thành với đầu "*" và tập hợp với đầu "o".
+func plantUMLDiamondKind(end string) models.RelationshipKind {
+	if end == "o" {
+		return models.Aggregation
+	}
+	return models.Composition
+}
+
+// convertMember rewrites a PlantUML member ("- name : String", "+ {abstract} area() : double",
+// "String getName()") into the draw.io notation understood by FeatureExtractor.
+// convertMember chuyển một thành viên PlantUML ("- name : String", "+ {abstract} area() : double",
+// "String getName()") sang ký pháp draw.io mà FeatureExtractor hiểu được.
+func (pp *PlantUMLParser) convertMember(member string) string {
+	member = strings.TrimSpace(member)
+	modifiers := ""
+	for _, tag := range []string{"{static}", "{classifier}", "{abstract}", "{field}", "{method}"} {
+		if strings.Contains(member, tag) {
+			switch tag {
+			case "{static}", "{classifier}":
+				modifiers += "static "
+			case "{abstract}":
+				modifiers += "abstract "
+			}
+			member = strings.TrimSpace(strings.Replace(member, tag, "", 1))
+		}
+	}
+
+	visibility := ""
+	if member != "" && strings.ContainsRune("+-#~", rune(member[0])) {
+		if member[0] != '~' {
+			visibility = member[:1] + " "
+		}
+		member = strings.TrimSpace(member[1:])
+	}
+	// Các từ khóa Java viết tường minh
+	for _, kw := range []string{"static", "abstract"} {
+		if strings.HasPrefix(member, kw+" ") {
+			modifiers += kw + " "
+			member = strings.TrimSpace(member[len(kw):])
+		}
+	}
+
+	open := strings.Index(member, "(")
+	close := strings.LastIndex(member, ")")
+	if open != -1 && close > open {
+		// Method: "name(params) : Type" or "Type name(params)"
+		// Phương thức: "tên(tham số) : Kiểu" hoặc "Kiểu tên(tham số)"
+		head := strings.TrimSpace(member[:open])
+		after := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(member[close+1:]), ":"))
+		name := head
+		if tokens := strings.Fields(head); len(tokens) >= 2 {
+			name = tokens[len(tokens)-1]
+			if after == "" {
+				after = strings.Join(tokens[:len(tokens)-1], " ")
+			}
+		}
+
+		var params []string
+		for _, p := range utils.SplitTopLevel(member[open+1:close], ',') {
+			if p = strings.TrimSpace(p); p != "" {
+				params = append(params, javaStyleParam(p))
+			}
+		}
+
+		converted := fmt.Sprintf("%s%s%s(%s)", visibility, modifiers, name, strings.Join(params, ", "))
+		if after != "" {
+			converted += ": " + after
+		}
+		return converted
+	}
+
+	// Field: "name : Type" or "Type name"
+	// Trường: "tên : Kiểu" hoặc "Kiểu tên"
+	if strings.Contains(member, ":") {
+		return visibility + modifiers + member
+	}
+	return visibility + modifiers + javaStyleParam(member)
+}
+
+// shortName drops the package part of a qualified name (com.acme.Foo -> Foo).
+// shortName bỏ phần gói của một tên đầy đủ (com.acme.Foo -> Foo).
+func (pp *PlantUMLParser) shortName(name string) string {
+	if idx := strings.LastIndex(name, "."); idx != -1 {
+		return name[idx+1:]
+	}
+	return name
+}
+
+// baseName drops type arguments from a type reference (Base<User> -> Base).
+// baseName bỏ các đối số kiểu khỏi một tham chiếu kiểu (Base<User> -> Base).
+func (pp *PlantUMLParser) baseName(name string) string {
+	if idx := strings.Index(name, "<"); idx != -1 {
+		return strings.TrimSpace(name[:idx])
+	}
+	return strings.TrimSpace(name)
+}
+
+// javaStyleParam normalizes "Type name" into "name: Type"; other forms are returned unchanged.
+// javaStyleParam chuẩn hóa "Kiểu tên" thành "tên: Kiểu"; các dạng khác được giữ nguyên.
+func javaStyleParam(p string) string {
+	if strings.Contains(p, ":") {
+		return p
+	}
+	// Tách tại khoảng trắng cuối cùng ở cấp cao nhất để giữ nguyên Map<String, Integer>
+	parts := utils.SplitTopLevel(p, ' ')
+	var tokens []string
+	for _, t := range parts {
+		if t = strings.TrimSpace(t); t != "" {
+			tokens = append(tokens, t)
+		}
+	}
+	if len(tokens) >= 2 {
+		return fmt.Sprintf("%s: %s", tokens[len(tokens)-1], strings.Join(tokens[:len(tokens)-1], " "))
+	}
+	return p
+}
@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"testing"
+)
+
+func TestPlantUMLConvertMember(t *testing.T) {
+	tests := []struct {
+		member string
+		want   string
+	}{
+		{member: "- name : String", want: "- name : String"},
+		{member: "-String name", want: "- name: String"},
+		{member: "#Map<String, Integer> counts", want: "# counts: Map<String, Integer>"},
+		{member: "+ {static} instance : App", want: "+ static instance : App"},
+		{member: "+ {abstract} area() : double", want: "+ abstract area(): double"},
+		{member: "String getName()", want: "getName(): String"},
+		{member: "+static void log(String msg, int level)", want: "+ static log(msg: String, level: int): void"},
+		{member: "~{method} reset()", want: "reset()"},
+	}
+
+	pp := NewPlantUMLParser(models.NewDiagnostics())
+	for _, tt := range tests {
+		t.Run(tt.member, func(t *testing.T) {
+			if got := pp.convertMember(tt.member); got != tt.want {
+				t.Errorf("convertMember(%q) = %q, want %q", tt.member, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractPlantUMLBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{name: "without markers", content: "class A\n", want: []string{"class A\n"}},
+		{name: "two blocks", content: "@startuml one\nclass A\n@enduml\n\n@startuml\nclass B\n@enduml\n", want: []string{"class A\n", "class B\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractPlantUMLBlocks(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractPlantUMLBlocks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlantUMLParserParse(t *testing.T) {
+	source := `
+' comment
+package com.shop {
+    abstract class Shape <<Entity>> {
+        - name : String
+        + {abstract} area() : double
+    }
+    interface Drawable
+    class Circle extends Shape implements Comparable<Circle> {
+        -double radius
+    }
+    enum Color {
+        RED, GREEN
+        BLUE
+    }
+}
+note top of Circle
+  ignored
+end note
+Circle .up.|> Drawable
+Order "1" *-- "many" Line : lines
+record Point(int x, int y)
+`
+	classes, err := NewPlantUMLParser(models.NewDiagnostics()).Parse(source)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		kind       models.ClassType
+		pkg        string
+		extends    string
+		implements []string
+		fields     []string // "tên: Kiểu", hoặc chỉ tên với hằng số enum
+	}{
+		{name: "Shape", kind: models.Abstract, pkg: "com.shop", fields: []string{"name: String"}},
+		{name: "Drawable", kind: models.Interface, pkg: "com.shop"},
+		{name: "Circle", kind: models.Class, pkg: "com.shop", extends: "Shape", implements: []string{"Comparable", "Drawable"}, fields: []string{"radius: double"}},
+		{name: "Comparable", kind: models.Interface, pkg: "com.shop"},
+		{name: "Color", kind: models.Enum, pkg: "com.shop", fields: []string{"RED", "GREEN", "BLUE"}},
+		{name: "Order", kind: models.Class, fields: []string{"lines: List<Line>"}},
+		{name: "Point", kind: models.Record, fields: []string{"x: int", "y: int"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cls, ok := classes[tt.name]
+			if !ok {
+				t.Fatalf("class %s not found", tt.name)
+			}
+			if cls.Type != tt.kind || cls.Package != tt.pkg || cls.Extends() != tt.extends || !reflect.DeepEqual(cls.Implements(), tt.implements) {
+				t.Errorf("%s = %s %q extends %q implements %v; want %s %q extends %q implements %v",
+					tt.name, cls.Type, cls.Package, cls.Extends(), cls.Implements(), tt.kind, tt.pkg, tt.extends, tt.implements)
+			}
+			var fields []string
+			for _, f := range cls.Fields {
+				if cls.Type == models.Enum && f.IsEnumConstant() {
+					fields = append(fields, f.Name)
+				} else {
+					fields = append(fields, f.Name+": "+f.Type)
+				}
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("fields of %s = %q, want %q", tt.name, fields, tt.fields)
+			}
+		})
+	}
+
+	if got := classes["Shape"].Stereotype; got != "Entity" {
+		t.Errorf("stereotype of Shape = %q, want Entity", got)
+	}
+}
+
+func TestPlantUMLParserWithoutClasses(t *testing.T) {
+	if _, err := NewPlantUMLParser(models.NewDiagnostics()).Parse("@startuml\nactor User\n@enduml\n"); err == nil {
+		t.Errorf("Parse accepted a diagram without classes")
+	}
+}
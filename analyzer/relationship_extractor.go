@@ -4,22 +4,41 @@ import (
 	"fmt"
 	"nUML/models"
 	"nUML/utils"
+	"regexp"
+	"strconv"
 	"strings"
 )
 
+// reMultiplicity matches a UML multiplicity: 1, *, 0..1, 1..*, 0..n.
+// reMultiplicity khớp bội số UML: 1, *, 0..1, 1..*, 0..n.
+var reMultiplicity = regexp.MustCompile(`^(\d+|\*|n)(\.\.(\d+|\*|n))?$`)
+
 // RelationshipExtractor is responsible for identifying relationships between classes.
 // RelationshipExtractor chịu trách nhiệm xác định các mối quan hệ giữa các lớp.
-type RelationshipExtractor struct{}
+type RelationshipExtractor struct {
+	edgeLabelExtractor *EdgeLabelExtractor
+	diagnostics        *models.Diagnostics
+}
 
-// NewRelationshipExtractor creates a new instance of RelationshipExtractor.
-// NewRelationshipExtractor tạo một phiên bản mới của RelationshipExtractor.
-func NewRelationshipExtractor() *RelationshipExtractor {
-	return &RelationshipExtractor{}
+// NewRelationshipExtractor creates a new instance of RelationshipExtractor that reports
+// auto-corrections and invalid edges to diagnostics.
+// NewRelationshipExtractor tạo một phiên bản mới của RelationshipExtractor, báo cáo
+// các lần tự động sửa và các cạnh không hợp lệ vào diagnostics.
+func NewRelationshipExtractor(diagnostics *models.Diagnostics) *RelationshipExtractor {
+	return &RelationshipExtractor{
+		edgeLabelExtractor: NewEdgeLabelExtractor(),
+		diagnostics:        diagnostics,
+	}
 }
 
-// Extract identifies relationships (extends, implements) from edges.
-// Extract xác định các mối quan hệ (kế thừa, triển khai) từ các cạnh.
+// Extract identifies relationships from edges: extends/implements (triangle arrows), and
+// association/aggregation/composition (open arrows, diamonds, plain lines) which become fields.
+// Extract xác định các mối quan hệ từ các cạnh: kế thừa/triển khai (mũi tên tam giác), và
+// liên kết/tập hợp/hợp thành (mũi tên mở, hình thoi, đường thẳng) được chuyển thành trường.
 func (re *RelationshipExtractor) Extract(cells []models.MxCell, classes map[string]*models.ClassModel) {
+	// Nhãn bội số / vai trò ở hai đầu của mỗi cạnh
+	labels := re.edgeLabelExtractor.Extract(cells)
+
 	for _, cell := range cells {
 		// Relationships (Edges)
 		// Các mối quan hệ (Cạnh)
@@ -31,59 +50,338 @@ func (re *RelationshipExtractor) Extract(cells []models.MxCell, classes map[stri
 				continue
 			} // Skip if either end is not a recognized class
 			style := cell.Style
+			startArrow := styleValue(style, "startArrow")
+			endArrow := styleValue(style, "endArrow")
+			dashed := styleValue(style, "dashed") == "1"
 
-			// Determine intended relationship from style
-			// Xác định mối quan hệ dự kiến từ kiểu
-			isImplements := strings.Contains(style, "dashed=1")
-			isExtends := strings.Contains(style, "endArrow=") // Broad check for solid arrows
-
-			// Logic Verification / Auto-Correction
-			// Xác minh logic / Tự động sửa lỗi
-			if isExtends && !isImplements {
-				// User drew "Extends". Check validity.
-				// Người dùng vẽ "Extends". Kiểm tra tính hợp lệ.
-				switch targetClass.Type {
-				case models.Interface:
-					// Class extends Interface -> ERROR. Should be Implements.
-					// Lớp kế thừa Giao diện -> LỖI. Nên là Triển khai.
-					sourceClass.Implements = append(sourceClass.Implements, targetClass.Name)
-					utils.LogVerbose(fmt.Sprintf("Auto-Correct: %s implements %s (was extends)", sourceClass.Name, targetClass.Name))
-				case models.Enum:
-					// Class extends Enum -> ERROR. Impossible in Java.
-					// Lớp kế thừa Enum -> LỖI. Không thể trong Java.
-					// Ignore it.
-					// Bỏ qua nó.
-					utils.LogVerbose(fmt.Sprintf("Auto-Correct: Ignoring %s extends Enum %s", sourceClass.Name, targetClass.Name))
-				default:
-					// Class extends Class -> OK
-					// Lớp kế thừa Lớp -> OK
-					sourceClass.Extends = targetClass.Name
-					utils.LogVerbose(fmt.Sprintf("Relationship: %s extends %s", sourceClass.Name, targetClass.Name))
+			// Mọi cạnh được ghi nhận như một Relationship, kể cả khi không có nhãn
+			rel := models.Relationship{EdgeID: cell.ID}
+			if labeled, ok := labels[cell.ID]; ok {
+				rel = *labeled
+			}
+			rel.Style = style
+			rel.Page = cell.Page
+			rel.SourceEnd.Navigable = isNavigableArrow(startArrow)
+			rel.TargetEnd.Navigable = isNavigableArrow(endArrow) || endArrow == ""
+
+			// Diamonds and open arrows / plain lines: the owning class gets a field
+			// Hình thoi và mũi tên mở / đường thẳng: lớp sở hữu nhận một trường
+			if strings.HasPrefix(startArrow, "diamond") {
+				// Hình thoi ở đầu nguồn: nguồn là tổng thể, đích là bộ phận
+				rel.Kind = diamondKind(styleValue(style, "startFill"))
+				re.associate(sourceClass, targetClass, rel, true)
+				continue
+			}
+			if strings.HasPrefix(endArrow, "diamond") {
+				rel.Kind = diamondKind(styleValue(style, "endFill"))
+				re.associate(sourceClass, targetClass, rel, false)
+				continue
+			}
+			if endArrow == "" && !dashed {
+				// draw.io vẽ cạnh không có endArrow với mũi tên thường: không rõ ý định, bỏ qua như trước
+				utils.LogVerbose(fmt.Sprintf("Relationship: edge %s from %s to %s has no endArrow, ignored (set endArrow=none for an association)", cell.ID, sourceClass.Name, targetClass.Name))
+				continue
+			}
+			// Nét đứt không có endArrow vẫn được hiểu là triển khai như trước
+			if isAssociationArrow(endArrow) {
+				if dashed {
+					// Mũi tên mở nét đứt là phụ thuộc (dependency), không sinh trường
+					rel.Kind = models.Dependency
+					re.record(sourceClass, targetClass, rel)
+					utils.LogVerbose(fmt.Sprintf("Relationship: %s depends on %s (no code generated)", sourceClass.Name, targetClass.Name))
+					continue
 				}
-			} else if isImplements {
-				// User drew "Implements".
-				// Người dùng vẽ "Implements".
-				if targetClass.Type != models.Interface {
-					// Implements non-interface?
-					// Triển khai cái không phải giao diện?
-					// Maybe they meant extends if it's a class?
-					// Có lẽ họ có ý định kế thừa nếu đó là một lớp?
-					// Let's stick to valid Java: Only interfaces can be implemented.
-					// Hãy tuân thủ Java hợp lệ: Chỉ các giao diện mới có thể được triển khai.
-					if targetClass.Type == models.Class || targetClass.Type == models.Abstract {
-						sourceClass.Extends = targetClass.Name
-						utils.LogVerbose(fmt.Sprintf("Auto-Correct: %s extends %s (was implements)", sourceClass.Name, targetClass.Name))
-					} else {
-						// E.g. Enum? Cannot implement enum.
-						// Ví dụ: Enum? Không thể triển khai enum.
-						sourceClass.Implements = append(sourceClass.Implements, targetClass.Name)
-						utils.LogVerbose(fmt.Sprintf("Relationship: %s implements %s", sourceClass.Name, targetClass.Name))
-					}
-				} else {
-					sourceClass.Implements = append(sourceClass.Implements, targetClass.Name)
-					utils.LogVerbose(fmt.Sprintf("Relationship: %s implements %s", sourceClass.Name, targetClass.Name))
+				rel.Kind = models.Association
+				if endArrow == "none" && isNavigableArrow(startArrow) {
+					// Chỉ có mũi tên ở đầu nguồn: đích điều hướng tới nguồn
+					re.associate(sourceClass, targetClass, rel, false)
+					continue
 				}
+				re.associate(sourceClass, targetClass, rel, true)
+				continue
 			}
+
+			// Determine intended relationship from style
+			// Xác định mối quan hệ dự kiến từ kiểu
+			isImplements := dashed
+			isExtends := endArrow != "" // Broad check for solid arrows (block, classic...)
+
+			re.link(sourceClass, targetClass, rel, isExtends, isImplements)
 		}
 	}
 }
+
+// diamondKind returns composition for a filled diamond and aggregation for a hollow one (fill=0).
+// diamondKind trả về hợp thành với hình thoi đặc và tập hợp với hình thoi rỗng (fill=0).
+func diamondKind(fill string) models.RelationshipKind {
+	if fill == "0" {
+		return models.Aggregation
+	}
+	return models.Composition
+}
+
+// isAssociationArrow reports whether an arrow style denotes an association end: an open arrow or an
+// explicit endArrow=none. A missing endArrow is drawn as a classic arrowhead and is not an association.
+// isAssociationArrow cho biết kiểu mũi tên có biểu thị một đầu liên kết hay không: mũi tên mở hoặc
+// endArrow=none tường minh. Thiếu endArrow được vẽ thành mũi tên thường và không phải là liên kết.
+func isAssociationArrow(arrow string) bool {
+	return arrow == "none" || isNavigableArrow(arrow)
+}
+
+// isNavigableArrow reports whether an arrow style is an open (navigability) arrow.
+// isNavigableArrow cho biết kiểu mũi tên có phải là mũi tên mở (khả năng điều hướng) hay không.
+func isNavigableArrow(arrow string) bool {
+	return arrow == "open" || arrow == "openThin" || arrow == "openAsync"
+}
+
+// styleValue returns the value of a "key=value" entry in a draw.io style string.
+// styleValue trả về giá trị của một mục "key=value" trong chuỗi kiểu của draw.io.
+func styleValue(style, key string) string {
+	for _, token := range strings.Split(style, ";") {
+		kv := strings.SplitN(strings.TrimSpace(token), "=", 2)
+		if len(kv) == 2 && kv[0] == key {
+			return kv[1]
+		}
+	}
+	return ""
+}
+
+// setStyleValue sets a "key=value" entry of a draw.io style string, keeping every other entry in place.
+// An empty value removes the entry.
+// setStyleValue đặt một mục "key=value" trong chuỗi kiểu của draw.io, giữ nguyên vị trí mọi mục khác.
+// Giá trị rỗng sẽ xóa mục đó.
+func setStyleValue(style, key, value string) string {
+	var tokens []string
+	found := false
+	for _, token := range strings.Split(style, ";") {
+		kv := strings.SplitN(strings.TrimSpace(token), "=", 2)
+		if len(kv) == 2 && kv[0] == key {
+			found = true
+			if value == "" {
+				continue
+			}
+			token = key + "=" + value
+		}
+		if token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	if !found && value != "" {
+		tokens = append(tokens, key+"="+value)
+	}
+	result := strings.Join(tokens, ";")
+	// Giữ dấu chấm phẩy cuối như kiểu gốc (draw.io luôn ghi nó)
+	if result != "" && (style == "" || strings.HasSuffix(style, ";")) {
+		result += ";"
+	}
+	return result
+}
+
+// parseEdgeLabel reads a multiplicity and a role name from an edge label such as "1..* lines" or "items".
+// parseEdgeLabel đọc bội số và tên vai trò từ nhãn của cạnh, ví dụ "1..* lines" hoặc "items".
+func parseEdgeLabel(raw string) (multiplicity, role string) {
+	for _, token := range strings.Fields(utils.CleanHTML(raw)) {
+		switch {
+		case reMultiplicity.MatchString(token):
+			if multiplicity == "" {
+				multiplicity = token
+			}
+		case role == "" && reIdentifier.MatchString(strings.TrimLeft(token, "+-#~")):
+			role = strings.TrimLeft(token, "+-#~")
+		}
+	}
+	return multiplicity, role
+}
+
+// link records an extends or implements relationship between two classes, auto-correcting
+// combinations that are not valid Java (e.g. a class "extending" an interface).
+// link ghi nhận mối quan hệ kế thừa hoặc triển khai giữa hai lớp, tự động sửa
+// các tổ hợp không hợp lệ trong Java (ví dụ một lớp "kế thừa" một giao diện).
+func (re *RelationshipExtractor) link(sourceClass, targetClass *models.ClassModel, rel models.Relationship, isExtends, isImplements bool) {
+	// Đối số kiểu từ nhãn của cạnh (<<bind>> T->User, <User>)
+	if len(rel.TypeArguments) == 0 {
+		rel.TypeArguments = parseTypeArguments(rel.Label, targetClass)
+	}
+
+	// Interface extends interface (Java has no "interface implements")
+	// Giao diện kế thừa giao diện (Java không có "interface implements")
+	if sourceClass.Type == models.Interface && targetClass.Type == models.Interface && (isExtends || isImplements) {
+		rel.Kind = models.Generalization
+		re.record(sourceClass, targetClass, rel)
+		utils.LogVerbose(fmt.Sprintf("Relationship: interface %s extends %s", sourceClass.Name, targetClass.Name))
+		return
+	}
+
+	// Logic Verification / Auto-Correction
+	// Xác minh logic / Tự động sửa lỗi
+	if isExtends && !isImplements {
+		// User drew "Extends". Check validity.
+		// Người dùng vẽ "Extends". Kiểm tra tính hợp lệ.
+		switch targetClass.Type {
+		case models.Interface:
+			// Class extends Interface -> ERROR. Should be Implements.
+			// Lớp kế thừa Giao diện -> LỖI. Nên là Triển khai.
+			rel.Kind = models.Realization
+			re.record(sourceClass, targetClass, rel)
+			utils.LogVerbose(fmt.Sprintf("Auto-Correct: %s implements %s (was extends)", sourceClass.Name, targetClass.Name))
+			re.report(models.SeverityWarning, models.RuleExtendsInterface, sourceClass, rel,
+				fmt.Sprintf("%s extends interface %s, treated as implements (kế thừa giao diện, được hiểu là triển khai)", sourceClass.Name, targetClass.Name),
+				setStyleValue(rel.Style, "dashed", "1"))
+		case models.Enum:
+			// Class extends Enum -> ERROR. Impossible in Java.
+			// Lớp kế thừa Enum -> LỖI. Không thể trong Java.
+			// Ignore it.
+			// Bỏ qua nó.
+			utils.LogVerbose(fmt.Sprintf("Auto-Correct: Ignoring %s extends Enum %s", sourceClass.Name, targetClass.Name))
+			re.report(models.SeverityError, models.RuleExtendsEnum, sourceClass, rel,
+				fmt.Sprintf("%s extends enum %s, which Java does not allow; the edge is ignored (không thể kế thừa enum)", sourceClass.Name, targetClass.Name), "")
+		default:
+			// Class extends Class -> OK
+			// Lớp kế thừa Lớp -> OK
+			rel.Kind = models.Generalization
+			re.record(sourceClass, targetClass, rel)
+			utils.LogVerbose(fmt.Sprintf("Relationship: %s extends %s", sourceClass.Name, targetClass.Name))
+		}
+	} else if isImplements {
+		// User drew "Implements".
+		// Người dùng vẽ "Implements".
+		if targetClass.Type != models.Interface {
+			// Implements non-interface?
+			// Triển khai cái không phải giao diện?
+			// Maybe they meant extends if it's a class?
+			// Có lẽ họ có ý định kế thừa nếu đó là một lớp?
+			// Let's stick to valid Java: Only interfaces can be implemented.
+			// Hãy tuân thủ Java hợp lệ: Chỉ các giao diện mới có thể được triển khai.
+			if targetClass.Type == models.Class || targetClass.Type == models.Abstract {
+				rel.Kind = models.Generalization
+				re.record(sourceClass, targetClass, rel)
+				utils.LogVerbose(fmt.Sprintf("Auto-Correct: %s extends %s (was implements)", sourceClass.Name, targetClass.Name))
+				re.report(models.SeverityWarning, models.RuleImplementsClass, sourceClass, rel,
+					fmt.Sprintf("%s implements class %s, treated as extends (triển khai một lớp, được hiểu là kế thừa)", sourceClass.Name, targetClass.Name),
+					setStyleValue(rel.Style, "dashed", ""))
+			} else {
+				// E.g. Enum? Cannot implement enum.
+				// Ví dụ: Enum? Không thể triển khai enum.
+				rel.Kind = models.Realization
+				re.record(sourceClass, targetClass, rel)
+				utils.LogVerbose(fmt.Sprintf("Relationship: %s implements %s", sourceClass.Name, targetClass.Name))
+			}
+		} else {
+			rel.Kind = models.Realization
+			re.record(sourceClass, targetClass, rel)
+			utils.LogVerbose(fmt.Sprintf("Relationship: %s implements %s", sourceClass.Name, targetClass.Name))
+		}
+	}
+}
+
+// record stores a relationship on its source class. A second superclass is reported, since Java
+// allows a single one (interfaces may extend several interfaces).
+// record lưu một quan hệ vào lớp nguồn của nó. Lớp cha thứ hai được báo cáo, vì Java
+// chỉ cho phép một lớp cha (giao diện có thể kế thừa nhiều giao diện).
+func (re *RelationshipExtractor) record(source, target *models.ClassModel, rel models.Relationship) {
+	if rel.Kind == models.Generalization && source.Type != models.Interface {
+		if super := source.Extends(); super != "" && super != target.Name {
+			re.report(models.SeverityError, models.RuleMultipleExtends, source, rel,
+				fmt.Sprintf("%s extends both %s and %s; only %s is generated (nhiều lớp cha)", source.Name, super, target.Name, super), "")
+		}
+	}
+	rel.Source, rel.Target = source.Name, target.Name
+	source.Relationships = append(source.Relationships, rel)
+}
+
+// report adds a diagnostic located at the edge, or at the source class when the edge has no cell (text sources).
+// When fixedStyle is set and the edge was drawn in draw.io, the diagnostic carries a fix rewriting the edge style.
+// report thêm một chẩn đoán định vị tại cạnh, hoặc tại lớp nguồn khi cạnh không có ô (nguồn văn bản).
+// Khi fixedStyle được đặt và cạnh được vẽ trong draw.io, chẩn đoán kèm theo chỉnh sửa ghi lại kiểu của cạnh.
+func (re *RelationshipExtractor) report(severity models.Severity, ruleID string, source *models.ClassModel, rel models.Relationship, message, fixedStyle string) {
+	if rel.EdgeID == "" {
+		re.diagnostics.Report(severity, ruleID, source.ID, source.Page, message)
+		return
+	}
+	if fixedStyle == "" || fixedStyle == rel.Style {
+		re.diagnostics.Report(severity, ruleID, rel.EdgeID, rel.Page, message)
+		return
+	}
+	re.diagnostics.ReportFix(severity, ruleID, rel.EdgeID, rel.Page, message,
+		models.Fix{Page: rel.Page, CellID: rel.EdgeID, Attribute: "style", Value: fixedStyle})
+}
+
+// associate records an association edge on its source class and adds the field that navigates it.
+// The field goes on the source when ownedBySource is set, otherwise on the target. Its multiplicity
+// and role come from the label at the opposite end, or from the middle label when that end has none.
+// associate ghi nhận cạnh liên kết trên lớp nguồn và thêm trường dùng để điều hướng theo cạnh đó.
+// Trường được đặt ở nguồn khi ownedBySource được bật, ngược lại ở đích. Bội số và vai trò lấy từ
+// nhãn ở đầu đối diện, hoặc từ nhãn giữa khi đầu đó không có nhãn.
+func (re *RelationshipExtractor) associate(source, target *models.ClassModel, rel models.Relationship, ownedBySource bool) {
+	re.record(source, target, rel)
+
+	owner, other, far := source, target, rel.TargetEnd
+	if !ownedBySource {
+		owner, other, far = target, source, rel.SourceEnd
+	}
+	if far.Multiplicity == "" && far.Role == "" {
+		far.Multiplicity, far.Role = parseEdgeLabel(rel.Label)
+	}
+	re.addAssociationField(owner, other, far.Multiplicity, far.Role)
+}
+
+// addAssociationField adds a field to owner that references target (association, aggregation, composition).
+// The role name becomes the field name; a "many" multiplicity (*, 0..*, 1..*) turns the type into List<T>.
+// addAssociationField thêm vào owner một trường tham chiếu tới target (liên kết, tập hợp, hợp thành).
+// Tên vai trò trở thành tên trường; bội số "nhiều" (*, 0..*, 1..*) biến kiểu thành List<T>.
+func (re *RelationshipExtractor) addAssociationField(owner, target *models.ClassModel, multiplicity, role string) {
+	fieldType := target.Name
+	fieldName := utils.SanitizeName(role)
+	many := isManyMultiplicity(multiplicity)
+
+	if many {
+		fieldType = "List<" + target.Name + ">"
+	}
+	if fieldName == "" {
+		fieldName = utils.LowercaseFirst(target.Name)
+		if many {
+			fieldName += "s"
+		}
+	}
+
+	// Không ghi đè trường đã được khai báo rõ ràng trên biểu đồ
+	for _, f := range owner.Fields {
+		if f.Name == fieldName {
+			return
+		}
+	}
+
+	owner.Fields = append(owner.Fields, models.Field{
+		Original:     strings.TrimSpace(multiplicity + " " + role),
+		Name:         fieldName,
+		Type:         fieldType,
+		Visibility:   "private",
+		Multiplicity: strings.TrimSpace(multiplicity),
+	})
+	utils.LogVerbose(fmt.Sprintf("Association: %s has %s %s", owner.Name, fieldType, fieldName))
+}
+
+// isManyMultiplicity reports whether a multiplicity allows more than one element (*, 0..*, 1..*, 0..n, 2..5).
+// isManyMultiplicity cho biết bội số có cho phép nhiều hơn một phần tử hay không (*, 0..*, 1..*, 0..n, 2..5).
+func isManyMultiplicity(multiplicity string) bool {
+	m := strings.ToLower(strings.TrimSpace(multiplicity))
+	if m == "" {
+		return false
+	}
+	if strings.Contains(m, "*") || strings.Contains(m, "many") {
+		return true
+	}
+
+	// Lấy cận trên: "0..n" -> "n", "2..5" -> "5", "3" -> "3"
+	upper := m
+	if idx := strings.LastIndex(m, ".."); idx != -1 {
+		upper = strings.TrimSpace(m[idx+2:])
+	}
+	if upper == "n" {
+		return true
+	}
+	if n, err := strconv.Atoi(upper); err == nil {
+		return n > 1
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// relationshipFixture returns the classes the relationship tests draw edges between.
+// relationshipFixture trả về các lớp mà kiểm thử quan hệ vẽ cạnh giữa chúng.
+func relationshipFixture() map[string]*models.ClassModel {
+	return map[string]*models.ClassModel{
+		"o": {ID: "o", Name: "Order", Type: models.Class},
+		"l": {ID: "l", Name: "Line", Type: models.Class},
+		"s": {ID: "s", Name: "Shape", Type: models.Interface},
+	}
+}
+
+// associationFields lists the fields of every class as "Owner.name: Type", sorted.
+// associationFields liệt kê các trường của mọi lớp dưới dạng "Lớp.tên: Kiểu", đã sắp xếp.
+func associationFields(classes map[string]*models.ClassModel) []string {
+	var fields []string
+	for _, cls := range classes {
+		for _, f := range cls.Fields {
+			fields = append(fields, cls.Name+"."+f.Name+": "+f.Type)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+func TestRelationshipExtractorKinds(t *testing.T) {
+	tests := []struct {
+		name   string
+		edge   models.MxCell
+		kind   models.RelationshipKind // Rỗng khi cạnh bị bỏ qua
+		fields []string
+	}{
+		{name: "filled diamond at source", edge: edgeCell("e", "startArrow=diamondThin;startFill=1;endArrow=none;", "o", "l"),
+			kind: models.Composition, fields: []string{"Order.line: Line"}},
+		{name: "hollow diamond at target", edge: edgeCell("e", "endArrow=diamondThin;endFill=0;", "o", "l"),
+			kind: models.Aggregation, fields: []string{"Line.order: Order"}},
+		{name: "open arrow", edge: edgeCell("e", "endArrow=open;", "o", "l"),
+			kind: models.Association, fields: []string{"Order.line: Line"}},
+		{name: "open arrow at source only", edge: edgeCell("e", "startArrow=open;endArrow=none;", "o", "l"),
+			kind: models.Association, fields: []string{"Line.order: Order"}},
+		{name: "plain line", edge: edgeCell("e", "endArrow=none;", "o", "l"),
+			kind: models.Association, fields: []string{"Order.line: Line"}},
+		{name: "dashed open arrow", edge: edgeCell("e", "endArrow=open;dashed=1;", "o", "l"), kind: models.Dependency},
+		{name: "missing endArrow", edge: edgeCell("e", "html=1;", "o", "l")},
+		{name: "dashed without endArrow", edge: edgeCell("e", "dashed=1;", "o", "s"), kind: models.Realization},
+		{name: "hollow triangle", edge: edgeCell("e", "endArrow=block;endFill=0;", "o", "l"), kind: models.Generalization},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classes := relationshipFixture()
+			NewRelationshipExtractor(models.NewDiagnostics()).Extract([]models.MxCell{tt.edge}, classes)
+
+			var kind models.RelationshipKind
+			if rels := classes["o"].Relationships; len(rels) == 1 {
+				kind = rels[0].Kind
+			} else if len(rels) > 1 {
+				t.Fatalf("relationships = %+v, want at most one", rels)
+			}
+			if kind != tt.kind {
+				t.Errorf("kind = %q, want %q", kind, tt.kind)
+			}
+			if got := associationFields(classes); !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("fields = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestRelationshipExtractorMultiplicity(t *testing.T) {
+	tests := []struct {
+		label string
+		want  []string
+	}{
+		{label: "", want: []string{"Order.line: Line"}},
+		{label: "0..1", want: []string{"Order.line: Line"}},
+		{label: "*", want: []string{"Order.lines: List<Line>"}},
+		{label: "1..* items", want: []string{"Order.items: List<Line>"}},
+		{label: "0..n", want: []string{"Order.lines: List<Line>"}},
+		{label: "2..5 +entries", want: []string{"Order.entries: List<Line>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.label, func(t *testing.T) {
+			classes := relationshipFixture()
+			edge := edgeCell("e", "endArrow=open;", "o", "l")
+			edge.Value = tt.label
+			NewRelationshipExtractor(models.NewDiagnostics()).Extract([]models.MxCell{edge}, classes)
+			if got := associationFields(classes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRelationshipExtractorKeepsDeclaredField(t *testing.T) {
+	classes := relationshipFixture()
+	classes["o"].Fields = []models.Field{{Name: "lines", Type: "Set<Line>"}}
+	edge := edgeCell("e", "endArrow=open;", "o", "l")
+	edge.Value = "*"
+	NewRelationshipExtractor(models.NewDiagnostics()).Extract([]models.MxCell{edge}, classes)
+	// Trường được khai báo trên biểu đồ không bị ghi đè bởi cạnh
+	if got, want := associationFields(classes), []string{"Order.lines: Set<Line>"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+}
+
+func TestRelationshipExtractorRecordsEdge(t *testing.T) {
+	classes := relationshipFixture()
+	edge := edgeCell("e", "startArrow=diamondThin;startFill=0;endArrow=open;", "o", "l")
+	edge.Page, edge.Value = "Sales", "has"
+	cells := []models.MxCell{edge, edgeLabelCell("a", "e", "1", "-0.8"), edgeLabelCell("b", "e", "0..* lines", "0.8")}
+	NewRelationshipExtractor(models.NewDiagnostics()).Extract(cells, classes)
+
+	// Mọi thông tin của cạnh được giữ lại trên quan hệ, không chỉ tên lớp đích
+	want := []models.Relationship{{
+		EdgeID:    "e",
+		Page:      "Sales",
+		Kind:      models.Aggregation,
+		Source:    "Order",
+		Target:    "Line",
+		Label:     "has",
+		SourceEnd: models.RelationshipEnd{Multiplicity: "1"},
+		TargetEnd: models.RelationshipEnd{Multiplicity: "0..*", Role: "lines", Navigable: true},
+		Style:     "startArrow=diamondThin;startFill=0;endArrow=open;",
+	}}
+	if got := classes["o"].Relationships; !reflect.DeepEqual(got, want) {
+		t.Errorf("relationships = %+v, want %+v", got, want)
+	}
+	if got, want := associationFields(classes), []string{"Order.lines: List<Line>"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("fields = %v, want %v", got, want)
+	}
+}
@@ -4,20 +4,37 @@ import (
 	"fmt"
 	"nUML/models"
 	"nUML/utils"
+	"sort"
+	"strings"
 )
 
 // HierarchyResolver is responsible for resolving inheritance and method overrides.
 // HierarchyResolver chịu trách nhiệm giải quyết việc kế thừa và ghi đè phương thức.
-type HierarchyResolver struct{}
+type HierarchyResolver struct {
+	diagnostics *models.Diagnostics
+}
+
+// NewHierarchyResolver creates a new instance of HierarchyResolver that reports cycles and clashes to diagnostics.
+// NewHierarchyResolver tạo một phiên bản mới của HierarchyResolver, báo cáo các vòng lặp và xung đột vào diagnostics.
+func NewHierarchyResolver(diagnostics *models.Diagnostics) *HierarchyResolver {
+	return &HierarchyResolver{diagnostics: diagnostics}
+}
 
-// NewHierarchyResolver creates a new instance of HierarchyResolver.
-// NewHierarchyResolver tạo một phiên bản mới của HierarchyResolver.
-func NewHierarchyResolver() *HierarchyResolver {
-	return &HierarchyResolver{}
+// inheritedMethod is a method reachable through the ancestry of a class, with type arguments substituted.
+// inheritedMethod là một phương thức có thể truy cập qua chuỗi tổ tiên của lớp, đã thay thế đối số kiểu.
+type inheritedMethod struct {
+	method models.Method
+	from   *models.ClassModel
 }
 
 // Resolve handles method inheritance and auto-overriding from abstract classes and interfaces.
+// Ancestors are resolved before their descendants and the whole ancestry is followed
+// (C extends B extends A, interfaces extending interfaces); inheritance cycles are reported and skipped.
+// Only concrete classes get stubs, so abstract methods pass through abstract classes to their subclasses.
 // Resolve xử lý việc kế thừa phương thức và tự động ghi đè từ các lớp trừu tượng và giao diện.
+// Các lớp tổ tiên được giải quyết trước lớp con và toàn bộ chuỗi tổ tiên được duyệt
+// (C extends B extends A, giao diện kế thừa giao diện); các vòng kế thừa được báo cáo và bỏ qua.
+// Chỉ lớp cụ thể nhận stub, nhờ đó phương thức trừu tượng đi qua lớp trừu tượng xuống các lớp con.
 func (hr *HierarchyResolver) Resolve(classes map[string]*models.ClassModel) {
 	// Helper to find class by Name (since relationships use Name, not ID)
 	// Trình trợ giúp để tìm lớp theo Tên (vì các mối quan hệ sử dụng Tên, không phải ID)
@@ -26,83 +43,277 @@ func (hr *HierarchyResolver) Resolve(classes map[string]*models.ClassModel) {
 		nameToClass[cls.Name] = cls
 	}
 
-	for _, cls := range classes {
-		if cls.Type == models.Interface || cls.Type == models.Enum {
+	order, cyclic := hr.topologicalOrder(nameToClass)
+	for _, cls := range order {
+		if cyclic[cls.Name] {
 			continue
 		}
 
-		// 1. Check Extends (Abstract Class)
-		// 1. Kiểm tra Extends (Lớp trừu tượng)
-		if cls.Extends != "" {
-			parent, ok := nameToClass[cls.Extends]
-			if ok && parent.Type == models.Abstract {
-				// Inherit abstract methods
-				// Kế thừa các phương thức trừu tượng
-				for _, pm := range parent.Methods {
-					if pm.IsAbstract {
-						// Check if cls already has it
-						// Kiểm tra xem cls đã có nó chưa
-						hasIt := false
-						for _, cm := range cls.Methods {
-							if cm.Name == pm.Name { // Simple name check for now
-								hasIt = true
-								break
-							}
-						}
-						if !hasIt {
-							// Add stub
-							// Thêm stub
-							newM := pm
-							newM.IsAbstract = false // Concrete implementation
-							newM.IsOverride = true
-							cls.Methods = append(cls.Methods, newM)
-							utils.LogVerbose(fmt.Sprintf("Auto-Override: %s inherits %s from %s", cls.Name, pm.Name, parent.Name))
-						}
-					}
+		required, inherited := hr.collect(cls, nameToClass)
+		hr.checkReturnTypes(cls, inherited, nameToClass)
+		// Lớp trừu tượng có thể để nguyên các phương thức trừu tượng; lớp con cụ thể sẽ nhận stub
+		if cls.Type == models.Interface || cls.Type == models.Enum || cls.Type == models.Abstract {
+			continue
+		}
+
+		for _, inh := range required {
+			// Check if cls already has it (same name and parameter types)
+			// Kiểm tra xem cls đã có nó chưa (cùng tên và kiểu tham số)
+			if hr.hasOverride(cls, inh.method) {
+				continue
+			}
+
+			// Add stub
+			// Thêm stub
+			newM := inh.method
+			newM.IsAbstract = false // Concrete implementation
+			newM.IsOverride = true
+			if inh.from.Type == models.Interface {
+				// Ensure public visibility for interface impl
+				// Đảm bảo phạm vi truy cập public cho việc triển khai giao diện
+				newM.Visibility = "public"
+				utils.LogVerbose(fmt.Sprintf("Auto-Implements: %s implements %s from %s", cls.Name, newM.Name, inh.from.Name))
+			} else {
+				utils.LogVerbose(fmt.Sprintf("Auto-Override: %s inherits %s from %s", cls.Name, newM.Name, inh.from.Name))
+			}
+			cls.Methods = append(cls.Methods, newM)
+		}
+	}
+}
+
+// collect walks the full ancestry of cls, nearest supertypes first (the superclass before the interfaces).
+// It returns the methods a concrete subclass must provide and every inherited method. The nearest
+// declaration of a signature decides: an abstract method of B hides the concrete one of A when
+// C extends B extends A, and a concrete superclass method implements the interface methods further up.
+// collect duyệt toàn bộ chuỗi tổ tiên của cls, kiểu cha gần nhất trước (lớp cha trước các giao diện).
+// Nó trả về các phương thức mà lớp con cụ thể phải cung cấp và mọi phương thức được kế thừa. Khai báo gần
+// nhất của một chữ ký quyết định: phương thức trừu tượng của B che phương thức cụ thể của A khi
+// C extends B extends A, và phương thức cụ thể của lớp cha triển khai các phương thức giao diện phía trên.
+func (hr *HierarchyResolver) collect(cls *models.ClassModel, nameToClass map[string]*models.ClassModel) ([]inheritedMethod, []inheritedMethod) {
+	var required, inherited []inheritedMethod
+	decided := make(map[string]bool)
+	visited := map[string]bool{cls.Name: true}
+
+	var walk func(c *models.ClassModel, bindings map[string]string)
+	walk = func(c *models.ClassModel, bindings map[string]string) {
+		// Lớp cha trước, sau đó mới đến các giao diện
+		var supertypes []models.Relationship
+		for _, kind := range []models.RelationshipKind{models.Generalization, models.Realization} {
+			for _, rel := range c.Relationships {
+				if rel.Kind == kind {
+					supertypes = append(supertypes, rel)
 				}
 			}
 		}
+		for _, rel := range supertypes {
+			super, ok := nameToClass[rel.Target]
+			if !ok || visited[super.Name] {
+				continue
+			}
+			visited[super.Name] = true
 
-		// 2. Check Implements (Interfaces)
-		// 2. Kiểm tra Implements (Giao diện)
-		for _, impl := range cls.Implements {
-			iface, ok := nameToClass[impl]
-			if ok && iface.Type == models.Interface {
-				for _, im := range iface.Methods {
-					// Check if cls already has it
-					// Kiểm tra xem cls đã có nó chưa
-					hasIt := false
-					for _, cm := range cls.Methods {
-						if cm.Name == im.Name {
-							hasIt = true
-							break
-						}
-					}
+			// Đối số kiểu của quan hệ, đã thay thế theo các đối số của lớp con (B<T> extends A<List<T>>)
+			var args []string
+			for _, arg := range rel.TypeArguments {
+				args = append(args, substituteType(arg, bindings))
+			}
+			superBindings := bindTypeArguments(super.TypeParameters, args)
+
+			for _, m := range super.Methods {
+				if m.IsStatic {
+					continue
+				}
+				method := inheritedMethod{method: substituteMethod(m, superBindings), from: super}
+				inherited = append(inherited, method)
+				sig := method.method.Signature()
+				if decided[sig] {
+					// Đã được quyết định bởi một khai báo gần hơn (kể cả khi kế thừa hình thoi)
+					continue
+				}
+				decided[sig] = true
+				isRequired := m.IsAbstract
+				if super.Type == models.Interface {
+					// Phương thức default không bắt buộc phải ghi đè
+					isRequired = m.Visibility != "default"
+				}
+				if isRequired {
+					required = append(required, method)
+				}
+			}
+			walk(super, superBindings)
+		}
+	}
+	walk(cls, nil)
+	return required, inherited
+}
+
+// topologicalOrder sorts the classes so that every supertype comes before its subtypes.
+// Classes on an inheritance cycle are reported and returned in the cyclic set.
+// topologicalOrder sắp xếp các lớp sao cho mọi kiểu cha đứng trước các kiểu con của nó.
+// Các lớp nằm trên một vòng kế thừa được báo cáo và trả về trong tập cyclic.
+func (hr *HierarchyResolver) topologicalOrder(nameToClass map[string]*models.ClassModel) ([]*models.ClassModel, map[string]bool) {
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := make(map[string]int)
+	cyclic := make(map[string]bool)
+	var order []*models.ClassModel
+	var path []string
 
-					// If Interface method is DEFAULT, we don't *need* to override, but user said "smart create".
-					// Nếu phương thức Giao diện là DEFAULT, chúng ta không *cần* phải ghi đè, nhưng người dùng đã nói "tạo thông minh".
-					// Actually, if it's default, we usually DON'T force override unless requested.
-					// Thực ra, nếu là default, chúng ta thường KHÔNG ép buộc ghi đè trừ khi được yêu cầu.
-					// But if it's abstract (no default), we MUST.
-					// Nhưng nếu nó là trừu tượng (không có default), chúng ta PHẢI ghi đè.
-					// The parser sets Visibility="default" for default methods.
-					// Trình phân tích cú pháp đặt Visibility="default" cho các phương thức mặc định.
-					isDefault := im.Visibility == "default"
-
-					if !hasIt && !isDefault {
-						// Add stub
-						// Thêm stub
-						newM := im
-						newM.IsAbstract = false
-						newM.IsOverride = true
-						// Ensure public visibility for interface impl
-						// Đảm bảo phạm vi truy cập public cho việc triển khai giao diện
-						newM.Visibility = "public"
-						cls.Methods = append(cls.Methods, newM)
-						utils.LogVerbose(fmt.Sprintf("Auto-Implements: %s implements %s from %s", cls.Name, im.Name, iface.Name))
+	var visit func(cls *models.ClassModel)
+	visit = func(cls *models.ClassModel) {
+		state[cls.Name] = inProgress
+		path = append(path, cls.Name)
+		for _, rel := range cls.Relationships {
+			if rel.Kind != models.Generalization && rel.Kind != models.Realization {
+				continue
+			}
+			super, ok := nameToClass[rel.Target]
+			if !ok {
+				continue
+			}
+			switch state[super.Name] {
+			case unvisited:
+				visit(super)
+			case inProgress:
+				// Vòng kế thừa: từ lần xuất hiện trước của super trên đường đi đến lớp hiện tại
+				start := 0
+				for i, name := range path {
+					if name == super.Name {
+						start = i
 					}
 				}
+				cycle := append(append([]string{}, path[start:]...), super.Name)
+				msg := fmt.Sprintf("Inheritance cycle: %s (vòng kế thừa)", strings.Join(cycle, " -> "))
+				utils.LogInfo("Warning (Cảnh báo): " + msg)
+				for _, name := range path[start:] {
+					cyclic[name] = true
+					nameToClass[name].LogEntries = append(nameToClass[name].LogEntries, msg)
+					hr.diagnostics.Report(models.SeverityError, models.RuleInheritanceCycle, nameToClass[name].ID, nameToClass[name].Page, msg)
+				}
 			}
 		}
+		path = path[:len(path)-1]
+		state[cls.Name] = done
+		order = append(order, cls)
+	}
+
+	// Duyệt theo tên để thứ tự (và các stub được thêm) ổn định giữa các lần chạy
+	var names []string
+	for name := range nameToClass {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if state[name] == unvisited {
+			visit(nameToClass[name])
+		}
+	}
+	return order, cyclic
+}
+
+// hasOverride reports whether cls already declares a method with the signature of inherited
+// (name plus parameter types), so overloads such as save(User) and save(List<User>) stay distinct.
+// hasOverride cho biết cls đã khai báo phương thức có chữ ký của inherited hay chưa
+// (tên và kiểu tham số), nhờ đó các nạp chồng như save(User) và save(List<User>) được phân biệt.
+func (hr *HierarchyResolver) hasOverride(cls *models.ClassModel, inherited models.Method) bool {
+	for _, cm := range cls.Methods {
+		if cm.Signature() == inherited.Signature() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReturnTypes reports every method of cls that redeclares an inherited method (abstract or concrete)
+// with an incompatible return type. Each method is compared with its nearest ancestor declaration.
+// Only return types that resolve to diagram classes or primitives are compared; external types such as
+// List<Item> against Collection<Item>, and type variables, cannot be checked and are left alone.
+// checkReturnTypes báo cáo mọi phương thức của cls khai báo lại một phương thức được kế thừa (trừu tượng
+// hay cụ thể) với kiểu trả về không tương thích. Mỗi phương thức được so với khai báo tổ tiên gần nhất.
+// Chỉ các kiểu trả về ứng với lớp của biểu đồ hoặc kiểu nguyên thủy được so sánh; các kiểu bên ngoài như
+// List<Item> so với Collection<Item>, và các biến kiểu, không thể kiểm tra nên được bỏ qua.
+func (hr *HierarchyResolver) checkReturnTypes(cls *models.ClassModel, inherited []inheritedMethod, nameToClass map[string]*models.ClassModel) {
+	for _, cm := range cls.Methods {
+		if cm.IsStatic {
+			continue
+		}
+		for _, inh := range inherited {
+			if cm.Signature() != inh.method.Signature() {
+				continue
+			}
+			if hr.isAssignable(cm.ReturnType, inh.method.ReturnType, nameToClass) {
+				break
+			}
+			if !hr.isKnownType(cm.ReturnType, nameToClass) || !hr.isKnownType(inh.method.ReturnType, nameToClass) {
+				utils.LogVerbose(fmt.Sprintf("Return type not checked: %s.%s returns %s, %s.%s returns %s",
+					cls.Name, cm.Signature(), cm.ReturnType, inh.from.Name, inh.method.Signature(), inh.method.ReturnType))
+				break
+			}
+			msg := fmt.Sprintf("Return type clash: %s.%s returns %s but %s.%s returns %s (xung đột kiểu trả về)",
+				cls.Name, cm.Signature(), cm.ReturnType, inh.from.Name, inh.method.Signature(), inh.method.ReturnType)
+			cls.LogEntries = append(cls.LogEntries, msg)
+			hr.diagnostics.Report(models.SeverityError, models.RuleReturnTypeClash, cls.ID, cls.Page, msg)
+			utils.LogInfo("Warning (Cảnh báo): " + msg)
+			break
+		}
+	}
+}
+
+// isAssignable reports whether an overriding return type is compatible with the overridden one:
+// the same type, or a diagram class that is a subtype of it (covariant return).
+// isAssignable cho biết kiểu trả về ghi đè có tương thích với kiểu bị ghi đè hay không:
+// cùng kiểu, hoặc một lớp của biểu đồ là kiểu con của nó (kiểu trả về hiệp biến).
+func (hr *HierarchyResolver) isAssignable(child, parent string, nameToClass map[string]*models.ClassModel) bool {
+	child, parent = strings.TrimSpace(child), strings.TrimSpace(parent)
+	if child == parent || parent == "Object" && child != "void" && !isPrimitive(child) {
+		return true
+	}
+
+	// Đi lên chuỗi kế thừa của lớp con (có giới hạn để tránh vòng lặp)
+	visited := make(map[string]bool)
+	queue := []string{child}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if name == parent {
+			return true
+		}
+		cls, ok := nameToClass[name]
+		if !ok || visited[name] {
+			continue
+		}
+		visited[name] = true
+		for _, rel := range cls.Relationships {
+			if rel.Kind == models.Generalization || rel.Kind == models.Realization {
+				queue = append(queue, rel.Target)
+			}
+		}
+	}
+	return false
+}
+
+// isKnownType reports whether a return type is void, a primitive or a non-generic diagram class,
+// i.e. a type whose subtypes are all known to the resolver.
+// isKnownType cho biết một kiểu trả về có phải là void, kiểu nguyên thủy hoặc một lớp không tổng quát
+// của biểu đồ hay không, tức là kiểu mà bộ giải quyết biết mọi kiểu con của nó.
+func (hr *HierarchyResolver) isKnownType(t string, nameToClass map[string]*models.ClassModel) bool {
+	t = strings.TrimSpace(t)
+	if t == "void" || isPrimitive(t) {
+		return true
+	}
+	cls, ok := nameToClass[t]
+	return ok && len(cls.TypeParameters) == 0
+}
+
+// isPrimitive reports whether a type is a Java primitive.
+// isPrimitive cho biết một kiểu có phải là kiểu nguyên thủy của Java hay không.
+func isPrimitive(t string) bool {
+	switch t {
+	case "boolean", "byte", "char", "short", "int", "long", "float", "double":
+		return true
 	}
+	return false
 }
@@ -0,0 +1,276 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// resolvePlantUML parses a PlantUML source and resolves its hierarchy, returning the classes and diagnostics.
+// resolvePlantUML phân tích một nguồn PlantUML và giải quyết cây kế thừa, trả về các lớp và chẩn đoán.
+func resolvePlantUML(t *testing.T, source string) (map[string]*models.ClassModel, *models.Diagnostics) {
+	diagnostics := models.NewDiagnostics()
+	classes, err := NewPlantUMLParser(diagnostics).Parse(source)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	NewHierarchyResolver(diagnostics).Resolve(classes)
+	return classes, diagnostics
+}
+
+// signatures returns the signatures of the methods of cls.
+// signatures trả về chữ ký các phương thức của cls.
+func signatures(cls *models.ClassModel) map[string]bool {
+	sigs := make(map[string]bool)
+	for _, m := range cls.Methods {
+		sigs[m.Signature()] = true
+	}
+	return sigs
+}
+
+func TestResolveReturnTypes(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		clash  bool
+	}{
+		{
+			name: "covariant external types",
+			source: `
+interface Store {
+    + getItems() : Collection<Item>
+    + total() : Number
+}
+class Shop implements Store {
+    + getItems() : List<Item>
+    + total() : Integer
+}
+class Item
+`,
+		},
+		{
+			name: "type variable",
+			source: `
+interface Source<T> {
+    + next() : T
+}
+class Users implements Source<T> {
+    + next() : User
+}
+class User
+`,
+		},
+		{
+			name: "covariant diagram class",
+			source: `
+abstract class Shape {
+    + {abstract} copy() : Shape
+}
+class Circle extends Shape {
+    + copy() : Circle
+}
+`,
+		},
+		{
+			name: "unrelated diagram classes",
+			source: `
+abstract class Shape {
+    + {abstract} copy() : Shape
+}
+class Circle extends Shape {
+    + copy() : Color
+}
+class Color
+`,
+			clash: true,
+		},
+		{
+			name: "different primitives",
+			source: `
+interface Sized {
+    + size() : int
+}
+class Box implements Sized {
+    + size() : long
+}
+`,
+			clash: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diagnostics := resolvePlantUML(t, tt.source)
+			clashes := 0
+			for _, d := range diagnostics.List() {
+				if d.RuleID == models.RuleReturnTypeClash {
+					clashes++
+				}
+			}
+			if got := clashes > 0; got != tt.clash {
+				t.Errorf("return-type-clash reported = %v, want %v (%v)", got, tt.clash, diagnostics.List())
+			}
+		})
+	}
+}
+
+func TestResolveOverloadStubs(t *testing.T) {
+	classes, diagnostics := resolvePlantUML(t, `
+interface Repository {
+    + save(user : User) : void
+    + save(users : List<User>) : void
+}
+class UserRepository implements Repository {
+    + save(user : User) : void
+}
+class User
+`)
+	sigs := signatures(classes["UserRepository"])
+	if len(classes["UserRepository"].Methods) != 2 || !sigs["save(User)"] || !sigs["save(List<User>)"] {
+		t.Errorf("methods of UserRepository = %v, want save(User) and a stub for save(List<User>)", sigs)
+	}
+	if diagnostics.HasErrors() {
+		t.Errorf("unexpected diagnostics: %v", diagnostics.List())
+	}
+}
+
+func TestResolveHierarchy(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		class  string
+		want   []string // Chữ ký các phương thức của lớp sau khi giải quyết
+	}{
+		{
+			name: "multi-level abstract",
+			source: `
+abstract class A {
+    + {abstract} run() : void
+}
+abstract class B extends A
+class C extends B
+`,
+			class: "C",
+			want:  []string{"run()"},
+		},
+		{
+			name: "implemented halfway",
+			source: `
+abstract class A {
+    + {abstract} run() : void
+}
+class B extends A {
+    + run() : void
+}
+class C extends B
+`,
+			class: "C",
+		},
+		{
+			name: "abstract redeclaration hides the concrete method",
+			source: `
+class A {
+    + run() : void
+}
+abstract class B extends A {
+    + {abstract} run() : void
+}
+class C extends B
+`,
+			class: "C",
+			want:  []string{"run()"},
+		},
+		{
+			name: "interface extending interface",
+			source: `
+interface Entity {
+    + getId() : long
+}
+interface Named extends Entity {
+    + getName() : String
+}
+class User implements Named
+`,
+			class: "User",
+			want:  []string{"getName()", "getId()"},
+		},
+		{
+			name: "diamond",
+			source: `
+interface Shape {
+    + area() : double
+}
+interface Solid extends Shape
+interface Flat extends Shape
+class Coin implements Solid, Flat
+`,
+			class: "Coin",
+			want:  []string{"area()"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classes, diagnostics := resolvePlantUML(t, tt.source)
+			var got []string
+			for _, m := range classes[tt.class].Methods {
+				got = append(got, m.Signature())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("methods of %s = %v, want %v", tt.class, got, tt.want)
+			}
+			if diagnostics.HasErrors() {
+				t.Errorf("unexpected diagnostics: %v", diagnostics.List())
+			}
+		})
+	}
+}
+
+func TestResolveCycle(t *testing.T) {
+	classes, diagnostics := resolvePlantUML(t, `
+abstract class A extends C {
+    + {abstract} run() : void
+}
+abstract class B extends A
+abstract class C extends B
+class D extends C
+`)
+
+	var cyclic []string
+	for _, d := range diagnostics.List() {
+		if d.RuleID == models.RuleInheritanceCycle {
+			cyclic = append(cyclic, d.CellID)
+		}
+	}
+	sort.Strings(cyclic)
+	if want := []string{"A", "B", "C"}; !reflect.DeepEqual(cyclic, want) {
+		t.Errorf("inheritance-cycle reported on %v, want %v", cyclic, want)
+	}
+	// Lớp nằm ngoài vòng vẫn được giải quyết mà không lặp vô hạn
+	if sigs := signatures(classes["D"]); len(sigs) != 1 || !sigs["run()"] {
+		t.Errorf("methods of D = %v, want run()", sigs)
+	}
+}
+
+func TestTopologicalOrder(t *testing.T) {
+	classes, _ := resolvePlantUML(t, `
+class C extends B
+class B extends A
+class A implements I
+interface I
+class Z
+`)
+	nameToClass := make(map[string]*models.ClassModel)
+	for _, cls := range classes {
+		nameToClass[cls.Name] = cls
+	}
+	order, cyclic := NewHierarchyResolver(models.NewDiagnostics()).topologicalOrder(nameToClass)
+	var got []string
+	for _, cls := range order {
+		got = append(got, cls.Name)
+	}
+	if want := []string{"I", "A", "B", "C", "Z"}; !reflect.DeepEqual(got, want) || len(cyclic) != 0 {
+		t.Errorf("topologicalOrder() = %v (cyclic %v), want %v", got, cyclic, want)
+	}
+}
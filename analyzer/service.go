@@ -10,32 +10,87 @@ type AnalyzerService struct {
 	// classExtractor is a pointer to a ClassExtractor instance used for extracting class information from source code.
 	// classExtractor là một con trỏ đến một instance của ClassExtractor được sử dụng để trích xuất thông tin lớp từ mã nguồn.
 	classExtractor        *ClassExtractor
+	compartmentExtractor  *CompartmentExtractor
 	featureExtractor      *FeatureExtractor
+	packageExtractor      *PackageExtractor
 	relationshipExtractor *RelationshipExtractor
 	hierarchyResolver     *HierarchyResolver
+	pageMerger            *PageMerger
+	mermaidParser         *MermaidParser
+	plantUMLParser        *PlantUMLParser
+	validator             *Validator
+	classSorter           *ClassSorter
+	// diagnostics collects the problems reported by every step of the analysis.
+	// diagnostics thu thập các vấn đề được báo cáo bởi mọi bước của quá trình phân tích.
+	diagnostics *models.Diagnostics
 }
 
-// NewAnalyzerService creates a new instance of AnalyzerService.
-// NewAnalyzerService tạo một phiên bản mới của AnalyzerService.
-func NewAnalyzerService() *AnalyzerService {
+// NewAnalyzerService creates a new instance of AnalyzerService returning the classes in the given order.
+// NewAnalyzerService tạo một phiên bản mới của AnalyzerService trả về các lớp theo thứ tự đã cho.
+func NewAnalyzerService(order ClassOrder) *AnalyzerService {
+	diagnostics := models.NewDiagnostics()
 	return &AnalyzerService{
-		classExtractor:        NewClassExtractor(),
-		featureExtractor:      NewFeatureExtractor(),
-		relationshipExtractor: NewRelationshipExtractor(),
-		hierarchyResolver:     NewHierarchyResolver(),
+		classExtractor:        NewClassExtractor(diagnostics),
+		compartmentExtractor:  NewCompartmentExtractor(diagnostics),
+		featureExtractor:      NewFeatureExtractor(diagnostics),
+		packageExtractor:      NewPackageExtractor(),
+		relationshipExtractor: NewRelationshipExtractor(diagnostics),
+		hierarchyResolver:     NewHierarchyResolver(diagnostics),
+		pageMerger:            NewPageMerger(),
+		mermaidParser:         NewMermaidParser(diagnostics),
+		plantUMLParser:        NewPlantUMLParser(diagnostics),
+		validator:             NewValidator(diagnostics),
+		classSorter:           NewClassSorter(order),
+		diagnostics:           diagnostics,
 	}
 }
 
-// AnalyzeDiagram processes the raw cells to produce a semantic model of the classes.
-// AnalyzeDiagram xử lý các ô thô để tạo ra mô hình ngữ nghĩa của các lớp.
-func (as *AnalyzerService) AnalyzeDiagram(cells []models.MxCell) map[string]*models.ClassModel {
-	// 1. Identify Classes
-	// 1. Xác định các lớp
-	classes := as.classExtractor.Extract(cells)
+// Diagnostics returns the problems found by the analyses run so far.
+// Diagnostics trả về các vấn đề được tìm thấy bởi các lần phân tích đã chạy.
+func (as *AnalyzerService) Diagnostics() *models.Diagnostics {
+	return as.diagnostics
+}
 
-	// 2. Identify Features (Fields, Methods in Swimlanes)
-	// 2. Xác định các đặc điểm (Trường, Phương thức trong Swimlanes)
-	as.featureExtractor.Extract(cells, classes)
+// AnalyzePages processes several diagram pages together. Classes sharing a name across pages
+// are merged, so edges drawn against a class on one page resolve to its definition on another.
+// AnalyzePages xử lý nhiều trang biểu đồ cùng nhau. Các lớp trùng tên giữa các trang được hợp nhất,
+// nhờ đó các cạnh vẽ tới một lớp ở trang này được giải quyết theo định nghĩa ở trang khác.
+func (as *AnalyzerService) AnalyzePages(pages []models.Diagram) []*models.ClassModel {
+	// 1-2. Xác định các lớp và đặc điểm cho từng trang (ID của cell chỉ duy nhất trong một trang)
+	perPage := make([]map[string]*models.ClassModel, len(pages))
+	position := 0
+	for i, page := range pages {
+		perPage[i] = as.extractClasses(page.Cells())
+		position = assignPositions(page.Cells(), perPage[i], position)
+	}
+
+	// Hợp nhất các lớp trùng tên giữa các trang
+	classes := as.pageMerger.Merge(pages, perPage)
+
+	// 3. Identify Relationships (per page, against the merged classes)
+	// 3. Xác định các mối quan hệ (theo trang, dựa trên các lớp đã hợp nhất)
+	for i, page := range pages {
+		as.relationshipExtractor.Extract(page.Cells(), perPage[i])
+	}
+
+	// 4. Resolve Inheritance
+	// 4. Giải quyết kế thừa
+	as.hierarchyResolver.Resolve(classes)
+
+	// 5. Validate the whole model
+	// 5. Kiểm tra toàn bộ mô hình
+	as.validator.Validate(classes)
+
+	return as.classSorter.Sort(classes)
+}
+
+// AnalyzeDiagram processes the raw cells to produce a semantic model of the classes, in the configured order.
+// AnalyzeDiagram xử lý các ô thô để tạo ra mô hình ngữ nghĩa của các lớp, theo thứ tự đã cấu hình.
+func (as *AnalyzerService) AnalyzeDiagram(cells []models.MxCell) []*models.ClassModel {
+	// 1-2. Identify Classes and Features
+	// 1-2. Xác định các lớp và đặc điểm
+	classes := as.extractClasses(cells)
+	assignPositions(cells, classes, 0)
 
 	// 3. Identify Relationships
 	// 3. Xác định các mối quan hệ
@@ -45,14 +100,78 @@ func (as *AnalyzerService) AnalyzeDiagram(cells []models.MxCell) map[string]*mod
 	// 4. Giải quyết kế thừa
 	as.hierarchyResolver.Resolve(classes)
 
-	return classes
+	// 5. Validate the whole model
+	// 5. Kiểm tra toàn bộ mô hình
+	as.validator.Validate(classes)
+
+	return as.classSorter.Sort(classes)
 	// kiến trúc:
-	// classes: map[string]*ClassModel
+	// classes: []*ClassModel, in the order chosen with NewAnalyzerService
 	// ClassModel:
 	// - Name
 	// - Type (Class, Abstract, Interface, Enum)
 	// - Fields []FieldModel
 	// - Methods []MethodModel
-	// - Extends string (for classes)
-	// - Implements []string (for classes)
+	// - Relationships []Relationship (Extends()/Implements() are derived from them)
+}
+
+// AnalyzeMermaid builds the semantic model from Mermaid classDiagram sources (.mmd files or Markdown blocks).
+// AnalyzeMermaid xây dựng mô hình ngữ nghĩa từ mã nguồn Mermaid classDiagram (tệp .mmd hoặc khối Markdown).
+func (as *AnalyzerService) AnalyzeMermaid(sources []string) ([]*models.ClassModel, error) {
+	// 1-3. Classes, features and relationships come straight from the text
+	// 1-3. Lớp, đặc điểm và mối quan hệ được lấy trực tiếp từ văn bản
+	classes, err := as.mermaidParser.Parse(sources...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Resolve Inheritance
+	// 4. Giải quyết kế thừa
+	as.hierarchyResolver.Resolve(classes)
+
+	// 5. Validate the whole model
+	// 5. Kiểm tra toàn bộ mô hình
+	as.validator.Validate(classes)
+
+	return as.classSorter.Sort(classes), nil
+}
+
+// AnalyzePlantUML builds the semantic model from PlantUML class diagrams (@startuml ... @enduml).
+// AnalyzePlantUML xây dựng mô hình ngữ nghĩa từ biểu đồ lớp PlantUML (@startuml ... @enduml).
+func (as *AnalyzerService) AnalyzePlantUML(sources []string) ([]*models.ClassModel, error) {
+	// 1-3. Classes, features and relationships come straight from the text
+	// 1-3. Lớp, đặc điểm và mối quan hệ được lấy trực tiếp từ văn bản
+	classes, err := as.plantUMLParser.Parse(sources...)
+	if err != nil {
+		return nil, err
+	}
+
+	// 4. Resolve Inheritance
+	// 4. Giải quyết kế thừa
+	as.hierarchyResolver.Resolve(classes)
+
+	// 5. Validate the whole model
+	// 5. Kiểm tra toàn bộ mô hình
+	as.validator.Validate(classes)
+
+	return as.classSorter.Sort(classes), nil
+}
+
+// extractClasses identifies the classes of one page together with their fields and methods.
+// extractClasses xác định các lớp của một trang cùng với các trường và phương thức của chúng.
+func (as *AnalyzerService) extractClasses(cells []models.MxCell) map[string]*models.ClassModel {
+	// 1. Identify Classes (swimlanes, then single-cell shapes)
+	// 1. Xác định các lớp (swimlanes, sau đó là các hình một ô)
+	classes := as.classExtractor.Extract(cells)
+	as.compartmentExtractor.Extract(cells, classes)
+
+	// 2. Identify Features (Fields, Methods in Swimlanes)
+	// 2. Xác định các đặc điểm (Trường, Phương thức trong Swimlanes)
+	as.featureExtractor.Extract(cells, classes)
+
+	// Packages from the enclosing package frames
+	// Gói từ các khung gói bao quanh
+	as.packageExtractor.Extract(cells, classes)
+
+	return classes
 }
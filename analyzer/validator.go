@@ -0,0 +1,80 @@
+package analyzer
+
+import (
+	"fmt"
+	"nUML/models"
+	"sort"
+	"strings"
+)
+
+// Validator is responsible for checks that need the whole model, after extraction and resolution.
+// Validator chịu trách nhiệm cho các kiểm tra cần toàn bộ mô hình, sau khi trích xuất và giải quyết.
+type Validator struct {
+	diagnostics *models.Diagnostics
+}
+
+// NewValidator creates a new instance of Validator that reports problems to diagnostics.
+// NewValidator tạo một phiên bản mới của Validator, báo cáo các vấn đề vào diagnostics.
+func NewValidator(diagnostics *models.Diagnostics) *Validator {
+	return &Validator{diagnostics: diagnostics}
+}
+
+// Validate reports classes of the same package and name within a page and enum methods that clash with constants.
+// Validate báo cáo các lớp cùng gói và cùng tên trong một trang và các phương thức enum xung đột với hằng số.
+func (v *Validator) Validate(classes map[string]*models.ClassModel) {
+	// Duyệt theo ID để lớp đầu tiên được xác định ổn định giữa các lần chạy
+	var list []*models.ClassModel
+	seen := make(map[*models.ClassModel]bool)
+	for _, cls := range classes {
+		if !seen[cls] {
+			seen[cls] = true
+			list = append(list, cls)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Page != list[j].Page {
+			return list[i].Page < list[j].Page
+		}
+		return list[i].ID < list[j].ID
+	})
+
+	first := make(map[string]*models.ClassModel)
+	for _, cls := range list {
+		// Các lớp trùng gói và tên giữa các trang đã được hợp nhất; trong cùng một trang chúng sinh ra cùng một tệp,
+		// còn các lớp cùng tên ở những gói khác nhau (com.acme.User và com.acme.billing.User) là hợp lệ
+		key := cls.Page + "/" + cls.Package + "." + cls.Name
+		if other, ok := first[key]; ok {
+			v.diagnostics.Report(models.SeverityError, models.RuleDuplicateClass, cls.ID, cls.Page,
+				fmt.Sprintf("Class %s is already defined by cell %s (tên lớp bị trùng)", cls.Name, other.ID))
+		} else {
+			first[key] = cls
+		}
+
+		if cls.Type == models.Enum {
+			v.validateEnum(cls)
+		}
+	}
+}
+
+// validateEnum reports methods named like one of the enum constants, and upper-case methods that are
+// most likely constants with arguments (RED(255, 0, 0)) read as operations.
+// validateEnum báo cáo các phương thức trùng tên với một hằng số enum, và các phương thức viết hoa
+// nhiều khả năng là hằng số có đối số (RED(255, 0, 0)) bị đọc thành phương thức.
+func (v *Validator) validateEnum(cls *models.ClassModel) {
+	constants := make(map[string]bool)
+	for _, f := range cls.Fields {
+		if f.IsEnumConstant() {
+			constants[f.Name] = true
+		}
+	}
+	for _, m := range cls.Methods {
+		switch {
+		case constants[m.Name]:
+			v.diagnostics.Report(models.SeverityError, models.RuleEnumMethodConflict, cls.ID, cls.Page,
+				fmt.Sprintf("Method %s.%s has the name of an enum constant (phương thức trùng tên hằng số enum)", cls.Name, m.Signature()))
+		case m.Name != "" && strings.ToUpper(m.Name) == m.Name && strings.ToLower(m.Name) != m.Name:
+			v.diagnostics.Report(models.SeverityError, models.RuleEnumMethodConflict, cls.ID, cls.Page,
+				fmt.Sprintf("Method %s.%s looks like an enum constant with arguments but is generated as a method (hằng số enum có đối số bị hiểu là phương thức)", cls.Name, m.Signature()))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"nUML/models"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// classCell, memberCell, packageCell and edgeCell build the draw.io cells used by the analyzer tests.
+// classCell, memberCell, packageCell và edgeCell tạo các ô draw.io dùng trong kiểm thử bộ phân tích.
+func classCell(id, parent, value string) models.MxCell {
+	return models.MxCell{ID: id, Parent: parent, Value: value, Style: "swimlane;fontStyle=1;", Vertex: "1"}
+}
+
+func memberCell(id, parent, value string) models.MxCell {
+	return models.MxCell{ID: id, Parent: parent, Value: value, Style: "text;align=left;", Vertex: "1"}
+}
+
+func packageCell(id, parent, value string) models.MxCell {
+	return models.MxCell{ID: id, Parent: parent, Value: value, Style: "shape=folder;", Vertex: "1"}
+}
+
+func edgeCell(id, style, source, target string) models.MxCell {
+	return models.MxCell{ID: id, Style: style, Edge: "1", Source: source, Target: target}
+}
+
+func TestValidatorRules(t *testing.T) {
+	const extends = "endArrow=block;endFill=0;"
+
+	tests := []struct {
+		name  string
+		cells []models.MxCell
+		want  []string // Các quy tắc được báo cáo, đã sắp xếp
+	}{
+		{
+			name:  "duplicate class",
+			cells: []models.MxCell{classCell("a", "", "User"), classCell("b", "", "User")},
+			want:  []string{models.RuleDuplicateClass},
+		},
+		{
+			name: "same name in two packages",
+			cells: []models.MxCell{
+				packageCell("p1", "", "com.acme"), classCell("a", "p1", "User"),
+				packageCell("p2", "", "com.acme.billing"), classCell("b", "p2", "User"),
+			},
+		},
+		{
+			name:  "untyped field",
+			cells: []models.MxCell{classCell("a", "", "User"), memberCell("f", "a", "- name")},
+			want:  []string{models.RuleUntypedField},
+		},
+		{
+			name:  "enum constants need no type",
+			cells: []models.MxCell{classCell("e", "", "«enum» Color"), memberCell("c", "e", "RED")},
+		},
+		{
+			name: "enum method conflicts",
+			cells: []models.MxCell{
+				classCell("e", "", "«enum» Color"), memberCell("c", "e", "RED"),
+				memberCell("m1", "e", "+ RED(): void"), memberCell("m2", "e", "GREEN(0, 255, 0)"),
+			},
+			want: []string{models.RuleEnumMethodConflict, models.RuleEnumMethodConflict},
+		},
+		{
+			name:  "extends enum",
+			cells: []models.MxCell{classCell("a", "", "Paint"), classCell("e", "", "«enum» Color"), edgeCell("x", extends, "a", "e")},
+			want:  []string{models.RuleExtendsEnum},
+		},
+		{
+			name: "multiple extends",
+			cells: []models.MxCell{
+				classCell("a", "", "A"), classCell("b", "", "B"), classCell("c", "", "C"),
+				edgeCell("x", extends, "c", "a"), edgeCell("y", extends, "c", "b"),
+			},
+			want: []string{models.RuleMultipleExtends},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			as := NewAnalyzerService(OrderName)
+			as.AnalyzeDiagram(tt.cells)
+			var got []string
+			for _, d := range as.Diagnostics().List() {
+				got = append(got, d.RuleID)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rules = %v, want %v (%v)", got, tt.want, as.Diagnostics().List())
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package exporter
+
+import "nUML/models"
+
+// DiagnosticExporter defines the interface for writing diagnostics in a machine readable format.
+// DiagnosticExporter định nghĩa giao diện để ghi các chẩn đoán ở định dạng máy đọc được.
+type DiagnosticExporter interface {
+	// Export renders the diagnostics found in the given diagram file.
+	// Export kết xuất các chẩn đoán được tìm thấy trong tệp biểu đồ đã cho.
+	Export(file string, diagnostics []models.Diagnostic) ([]byte, error)
+}
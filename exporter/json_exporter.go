@@ -0,0 +1,68 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"nUML/models"
+	"path/filepath"
+)
+
+// jsonReport is the document written by JSONExporter.
+// jsonReport là tài liệu được ghi bởi JSONExporter.
+type jsonReport struct {
+	File        string              `json:"file"`
+	Errors      int                 `json:"errors"`
+	Warnings    int                 `json:"warnings"`
+	Notes       int                 `json:"notes"`
+	Diagnostics []models.Diagnostic `json:"diagnostics"`
+}
+
+// JSONExporter implements DiagnosticExporter with a plain JSON document for custom tooling.
+// JSONExporter triển khai DiagnosticExporter bằng một tài liệu JSON đơn giản cho các công cụ tùy chỉnh.
+type JSONExporter struct{}
+
+// NewJSONExporter creates a new instance of JSONExporter.
+// NewJSONExporter tạo một phiên bản mới của JSONExporter.
+func NewJSONExporter() *JSONExporter {
+	return &JSONExporter{}
+}
+
+// Export renders {"file", "errors", "warnings", "notes", "diagnostics": [...]}.
+// Export kết xuất {"file", "errors", "warnings", "notes", "diagnostics": [...]}.
+func (je *JSONExporter) Export(file string, diagnostics []models.Diagnostic) ([]byte, error) {
+	report := jsonReport{File: filepath.ToSlash(file), Diagnostics: diagnostics}
+	if report.Diagnostics == nil {
+		// Luôn ghi một mảng, kể cả khi không có chẩn đoán nào
+		report.Diagnostics = []models.Diagnostic{}
+	}
+	for _, d := range diagnostics {
+		switch d.Severity {
+		case models.SeverityError:
+			report.Errors++
+		case models.SeverityWarning:
+			report.Warnings++
+		case models.SeverityNote:
+			report.Notes++
+		}
+	}
+
+	data, err := encodeJSON(report)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding JSON (lỗi mã hóa JSON): %v", err)
+	}
+	return data, nil
+}
+
+// encodeJSON indents v without escaping <, > and &, which appear in messages ("X -> Y", List<User>).
+// encodeJSON thụt lề v mà không thoát các ký tự <, > và &, vốn xuất hiện trong thông điệp ("X -> Y", List<User>).
+func encodeJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
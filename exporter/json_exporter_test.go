@@ -0,0 +1,67 @@
+package exporter
+
+import (
+	"flag"
+	"nUML/models"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update rewrites the golden files with the current output: go test ./exporter -update
+// update ghi lại các tệp mẫu bằng kết quả hiện tại: go test ./exporter -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// fixtureFile and fixtureDiagnostics are the input shared by the exporter tests: one diagnostic per severity,
+// on a named page and on a text source without pages.
+// fixtureFile và fixtureDiagnostics là đầu vào dùng chung cho các kiểm thử exporter: một chẩn đoán cho mỗi
+// mức độ, trên một trang có tên và trên một nguồn văn bản không có trang.
+var fixtureFile = filepath.Join("diagrams", "shop.drawio")
+
+var fixtureDiagnostics = []models.Diagnostic{
+	{Severity: models.SeverityError, RuleID: models.RuleExtendsEnum, CellID: "e1", Page: "Model",
+		Message: "Paint extends enum Color, which Java does not allow; the edge is ignored"},
+	{Severity: models.SeverityWarning, RuleID: models.RuleUntypedField, CellID: "f2", Page: "Model",
+		Message: "Field User.name has no type, String is assumed"},
+	{Severity: models.SeverityNote, RuleID: models.RuleMemberNotation, CellID: "Order",
+		Message: "Member \"List<Line> lines\" of Order is in Java notation, UML is \"lines: List<Line>\""},
+}
+
+// checkGolden compares got with testdata/name, or rewrites the file when -update is set.
+// checkGolden so sánh got với testdata/name, hoặc ghi lại tệp khi có cờ -update.
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("%s differs from the golden file:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func TestJSONExporterExport(t *testing.T) {
+	tests := []struct {
+		golden      string
+		diagnostics []models.Diagnostic
+	}{
+		{golden: "diagnostics.json", diagnostics: fixtureDiagnostics},
+		// Không có chẩn đoán: vẫn ghi một mảng rỗng thay vì null
+		{golden: "empty.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			got, err := NewJSONExporter().Export(fixtureFile, tt.diagnostics)
+			if err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+			checkGolden(t, tt.golden, got)
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package exporter
+
+import (
+	"fmt"
+	"nUML/models"
+	"path/filepath"
+)
+
+const (
+	// sarifVersion and sarifSchema identify the SARIF 2.1.0 format.
+	// sarifVersion và sarifSchema định danh định dạng SARIF 2.1.0.
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	// toolURI is the home page of nUML, shown by code-scanning dashboards.
+	// toolURI là trang chủ của nUML, được hiển thị bởi các bảng điều khiển quét mã.
+	toolURI = "https://github.com/Nguyen-Agn/nUML"
+)
+
+// SARIF 2.1.0 document (only the properties nUML fills in).
+// Tài liệu SARIF 2.1.0 (chỉ các thuộc tính mà nUML điền vào).
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool      sarifTool       `json:"tool"`
+	Artifacts []sarifArtifact `json:"artifacts"`
+	Results   []sarifResult   `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifArtifact struct {
+	Location sarifArtifactLocation `json:"location"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	RuleIndex  int               `json:"ruleIndex"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations"`
+	Properties map[string]string `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI   string `json:"uri"`
+	Index int    `json:"index"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// SARIFExporter implements DiagnosticExporter with SARIF 2.1.0, the format read by code-scanning dashboards.
+// SARIFExporter triển khai DiagnosticExporter bằng SARIF 2.1.0, định dạng được các bảng điều khiển quét mã đọc.
+type SARIFExporter struct{}
+
+// NewSARIFExporter creates a new instance of SARIFExporter.
+// NewSARIFExporter tạo một phiên bản mới của SARIFExporter.
+func NewSARIFExporter() *SARIFExporter {
+	return &SARIFExporter{}
+}
+
+// Export renders one run whose results point at the diagram file; the mxCell id and page name
+// are given as a logical location ("Page/cell") and as result properties.
+// Export kết xuất một lượt chạy với các kết quả trỏ tới tệp biểu đồ; id của mxCell và tên trang
+// được cung cấp dưới dạng vị trí logic ("Trang/ô") và thuộc tính của kết quả.
+func (se *SARIFExporter) Export(file string, diagnostics []models.Diagnostic) ([]byte, error) {
+	uri := filepath.ToSlash(file)
+
+	// Mọi quy tắc đã biết được liệt kê theo thứ tự của danh mục để ruleIndex ổn định
+	ruleIndex := make(map[string]int)
+	var rules []sarifRule
+	for _, rule := range models.Rules {
+		ruleIndex[rule.ID] = len(rules)
+		rules = append(rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{Text: rule.Description},
+			DefaultConfiguration: sarifConfiguration{Level: string(rule.Severity)},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, d := range diagnostics {
+		index, ok := ruleIndex[d.RuleID]
+		if !ok {
+			// Quy tắc chưa có trong danh mục vẫn được khai báo để kết quả hợp lệ
+			index = len(rules)
+			ruleIndex[d.RuleID] = index
+			rules = append(rules, sarifRule{ID: d.RuleID, ShortDescription: sarifMessage{Text: d.RuleID}, DefaultConfiguration: sarifConfiguration{Level: string(d.Severity)}})
+		}
+
+		qualified := d.CellID
+		if d.Page != "" {
+			qualified = d.Page + "/" + d.CellID
+		}
+		results = append(results, sarifResult{
+			RuleID:    d.RuleID,
+			RuleIndex: index,
+			Level:     string(d.Severity),
+			Message:   sarifMessage{Text: d.Message},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}},
+				LogicalLocations: []sarifLogicalLocation{{Name: d.CellID, FullyQualifiedName: qualified, Kind: "element"}},
+			}},
+			Properties: map[string]string{"cellId": d.CellID, "page": d.Page},
+		})
+	}
+
+	log := sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs: []sarifRun{{
+			Tool:      sarifTool{Driver: sarifDriver{Name: "nUML", InformationURI: toolURI, Rules: rules}},
+			Artifacts: []sarifArtifact{{Location: sarifArtifactLocation{URI: uri}}},
+			Results:   results,
+		}},
+	}
+
+	data, err := encodeJSON(log)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding SARIF (lỗi mã hóa SARIF): %v", err)
+	}
+	return data, nil
+}
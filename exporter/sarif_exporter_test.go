@@ -0,0 +1,99 @@
+package exporter
+
+import (
+	"encoding/json"
+	"nUML/models"
+	"testing"
+)
+
+func TestSARIFExporterExport(t *testing.T) {
+	got, err := NewSARIFExporter().Export(fixtureFile, fixtureDiagnostics)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	checkGolden(t, "diagnostics.sarif", got)
+
+	// Các thuộc tính mà SARIF 2.1.0 yêu cầu, kiểm tra độc lập với tệp mẫu
+	var log struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    string `json:"ruleId"`
+				RuleIndex int    `json:"ruleIndex"`
+				Level     string `json:"level"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+					} `json:"physicalLocation"`
+					LogicalLocations []struct {
+						Name               string `json:"name"`
+						FullyQualifiedName string `json:"fullyQualifiedName"`
+					} `json:"logicalLocations"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(got, &log); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 || len(log.Runs[0].Results) != len(fixtureDiagnostics) {
+		t.Fatalf("document = version %q with %d runs, want 2.1.0 with one run of %d results", log.Version, len(log.Runs), len(fixtureDiagnostics))
+	}
+
+	rules := log.Runs[0].Tool.Driver.Rules
+	for i, result := range log.Runs[0].Results {
+		d := fixtureDiagnostics[i]
+		if result.RuleID != d.RuleID || rules[result.RuleIndex].ID != d.RuleID || result.Level != string(d.Severity) {
+			t.Errorf("result %d = %s (rule %d) at %s, want %s at %s", i, result.RuleID, result.RuleIndex, result.Level, d.RuleID, d.Severity)
+		}
+		location := result.Locations[0]
+		if uri := location.PhysicalLocation.ArtifactLocation.URI; uri != "diagrams/shop.drawio" {
+			t.Errorf("uri of result %d = %q, want diagrams/shop.drawio", i, uri)
+		}
+		want := d.CellID
+		if d.Page != "" {
+			want = d.Page + "/" + d.CellID
+		}
+		if logical := location.LogicalLocations[0]; logical.Name != d.CellID || logical.FullyQualifiedName != want {
+			t.Errorf("logical location of result %d = %s (%s), want %s (%s)", i, logical.Name, logical.FullyQualifiedName, d.CellID, want)
+		}
+	}
+}
+
+func TestSARIFExporterUnknownRule(t *testing.T) {
+	got, err := NewSARIFExporter().Export("a.drawio", []models.Diagnostic{{Severity: models.SeverityWarning, RuleID: "custom-rule", CellID: "c1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleIndex int `json:"ruleIndex"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(got, &log); err != nil {
+		t.Fatal(err)
+	}
+	// Quy tắc không có trong danh mục được thêm vào cuối danh sách quy tắc
+	rules := log.Runs[0].Tool.Driver.Rules
+	if index := log.Runs[0].Results[0].RuleIndex; index != len(models.Rules) || rules[index].ID != "custom-rule" {
+		t.Errorf("ruleIndex = %d, want %d declaring custom-rule", index, len(models.Rules))
+	}
+}
@@ -0,0 +1,165 @@
+package generator
+
+import (
+	"fmt"
+	"nUML/models"
+	"nUML/output"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ReportFile is the name of the generation report written next to the generated files.
+// ReportFile là tên của báo cáo tạo code được ghi cạnh các tệp được tạo.
+const ReportFile = "Report.md"
+
+// WriteOptions controls how ArtifactWriter treats files that already exist.
+// WriteOptions điều khiển cách ArtifactWriter xử lý các tệp đã tồn tại.
+type WriteOptions struct {
+	Overwrite bool // Overwrite existing files (-o) // Ghi đè tệp hiện có (-o)
+	Merge     bool // Merge into existing files (-m) // Hợp nhất vào tệp hiện có (-m)
+	DryRun    bool // Plan the actions without writing (--dry-run) // Lập kế hoạch mà không ghi (--dry-run)
+	Workers   int  // Files generated and written in parallel (-j) // Số tệp được tạo và ghi song song (-j)
+}
+
+// FileResult is the outcome of generating and writing one file.
+// FileResult là kết quả của việc tạo và ghi một tệp.
+type FileResult struct {
+	ClassName   string   // Class the file was generated from // Lớp mà tệp được tạo từ đó
+	Path        string   // Path relative to the sink, with forward slashes // Đường dẫn tương đối với sink, dùng dấu gạch chéo xuôi
+	Action      string   // create, overwrite, merge or skip // create, overwrite, merge hoặc skip
+	Notes       []string // Notes of the merge // Ghi chú của việc hợp nhất
+	ReportEntry string   // Markdown entry for Report.md // Mục markdown cho Report.md
+	Err         error    // Generation, merge or write error // Lỗi tạo, hợp nhất hoặc ghi
+}
+
+// ArtifactWriter generates classes and writes them to a sink, honouring the write options.
+// ArtifactWriter tạo các lớp và ghi chúng vào một sink, tuân theo các tùy chọn ghi.
+type ArtifactWriter struct {
+	gen     CodeGenerator
+	merger  *JavaMerger
+	options WriteOptions
+}
+
+// NewArtifactWriter creates an ArtifactWriter for a generator.
+// NewArtifactWriter tạo một ArtifactWriter cho một bộ sinh mã.
+func NewArtifactWriter(gen CodeGenerator, options WriteOptions) *ArtifactWriter {
+	return &ArtifactWriter{gen: gen, merger: NewJavaMerger(), options: options}
+}
+
+// WriteAll generates every class and writes it to the sink with a pool of workers. Nothing is written in
+// dry-run mode. Results keep the order of classes whatever the number of workers; sinks other than the
+// filesystem (stdout, archives) receive the files one at a time, in that order.
+// WriteAll tạo mọi lớp và ghi vào sink bằng một nhóm worker. Không ghi gì ở chế độ chạy thử. Kết quả giữ
+// thứ tự của classes bất kể số worker; các sink khác hệ thống tệp (stdout, tệp nén) nhận tệp lần lượt
+// từng cái, theo thứ tự đó.
+func (aw *ArtifactWriter) WriteAll(classes []*models.ClassModel, sink output.Sink) []FileResult {
+	results := make([]FileResult, len(classes))
+	workers := aw.options.Workers
+	if _, ok := sink.(*output.FileSink); !ok || workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = aw.WriteClass(classes[i], sink)
+			}
+		}()
+	}
+	for i := range classes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// WriteClass generates one class, merges it with the existing file when asked and writes it to the sink.
+// WriteClass tạo một lớp, hợp nhất với tệp hiện có khi được yêu cầu và ghi vào sink.
+func (aw *ArtifactWriter) WriteClass(cls *models.ClassModel, sink output.Sink) FileResult {
+	result := FileResult{ClassName: cls.Name}
+	artifact, err := aw.gen.Generate(cls)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to generate code (không thể tạo code): %v", err)
+		return result
+	}
+
+	// Handle File Writing
+	// Xử lý ghi tệp
+	result.Path = filepath.ToSlash(artifact.FileName)
+	result.Action = aw.PlannedAction(sink, artifact.FileName)
+	result.ReportEntry = artifact.ReportEntry
+	content := artifact.Content
+	switch result.Action {
+	case "merge":
+		// Hợp nhất: giữ thân hàm, import và thành viên được viết tay
+		existing, err := sink.ReadFile(artifact.FileName)
+		if err == nil {
+			content, result.Notes, err = aw.merger.Merge(string(existing), artifact.Content)
+		}
+		if err != nil {
+			result.Err = fmt.Errorf("merge failed, file left untouched (hợp nhất thất bại, tệp được giữ nguyên): %v", err)
+			return result
+		}
+		// Ghi chú được thêm vào danh sách của mục, trước dòng trống kết thúc
+		result.ReportEntry = strings.TrimSuffix(result.ReportEntry, "\n")
+		for _, note := range result.Notes {
+			result.ReportEntry += fmt.Sprintf("- [!] %s\n", note)
+		}
+		result.ReportEntry += "\n"
+	case "skip":
+		result.ReportEntry = fmt.Sprintf("# %s [Skipped (Đã bỏ qua)]\n- File exists and neither -o nor -m set.\n\n", cls.Name)
+		return result
+	}
+
+	if aw.options.DryRun {
+		return result
+	}
+	result.Err = sink.WriteFile(artifact.FileName, []byte(content))
+	return result
+}
+
+// WriteReport writes Report.md for the results, in their order, and returns its own result.
+// WriteReport ghi Report.md cho các kết quả, theo thứ tự của chúng, và trả về kết quả của chính nó.
+func (aw *ArtifactWriter) WriteReport(results []FileResult, sink output.Sink) FileResult {
+	report := FileResult{ClassName: ReportFile, Path: ReportFile, Action: aw.PlannedAction(sink, ReportFile)}
+	if !aw.options.DryRun {
+		report.Err = sink.WriteFile(ReportFile, []byte(BuildReport(results)))
+	}
+	return report
+}
+
+// PlannedAction returns what happens to a file: "create", "overwrite" (-o), "merge" (-m) or "skip".
+// PlannedAction trả về điều sẽ xảy ra với một tệp: "create", "overwrite" (-o), "merge" (-m) hoặc "skip".
+func (aw *ArtifactWriter) PlannedAction(sink output.Sink, path string) string {
+	switch {
+	case !sink.Exists(path):
+		return "create"
+	case aw.options.Merge && path != ReportFile:
+		return "merge"
+	case aw.options.Overwrite || path == ReportFile:
+		// Report.md luôn được tạo lại
+		return "overwrite"
+	}
+	return "skip"
+}
+
+// BuildReport assembles Report.md from the results, in their order.
+// BuildReport ghép Report.md từ các kết quả, theo thứ tự của chúng.
+func BuildReport(results []FileResult) string {
+	var overallReport strings.Builder
+	overallReport.WriteString("# Generation Report (Báo cáo tạo code)\n\n")
+	for _, r := range results {
+		if r.Err != nil {
+			overallReport.WriteString(fmt.Sprintf("# %s [Failed (Thất bại)]\n- %v\n\n", r.ClassName, r.Err))
+		} else {
+			overallReport.WriteString(r.ReportEntry)
+		}
+	}
+	return overallReport.String()
+}
@@ -0,0 +1,126 @@
+package generator
+
+import (
+	"fmt"
+	"nUML/models"
+	"nUML/output"
+	"strings"
+	"testing"
+)
+
+// stubGenerator generates a class with a single method, whose body the merge must keep.
+type stubGenerator struct{}
+
+func (stubGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, error) {
+	if cls.Name == "Broken" {
+		return nil, fmt.Errorf("boom")
+	}
+	return &GeneratedArtifact{
+		FileName:    "models/" + cls.Name + ".java",
+		Content:     "public class " + cls.Name + " {\n\n    public void run() {\n        //Add your code here\n    }\n\n}\n",
+		ReportEntry: "# " + cls.Name + " [.]\n\n",
+	}, nil
+}
+
+func TestArtifactWriterWriteAll(t *testing.T) {
+	const handWritten = "public class User {\n\n    public void run() {\n        System.out.println(\"hi\");\n    }\n\n}\n"
+
+	tests := []struct {
+		name        string
+		options     WriteOptions
+		existing    map[string]string
+		wantActions []string
+		wantFiles   map[string]string // Nội dung mong đợi; "" nghĩa là tệp không tồn tại
+	}{
+		{
+			name:        "creates missing files",
+			options:     WriteOptions{Workers: 4},
+			wantActions: []string{"create", "create"},
+			wantFiles:   map[string]string{"models/User.java": "public class User", "models/Order.java": "public class Order"},
+		},
+		{
+			name:        "skips existing files by default",
+			options:     WriteOptions{},
+			existing:    map[string]string{"models/User.java": handWritten},
+			wantActions: []string{"skip", "create"},
+			wantFiles:   map[string]string{"models/User.java": "System.out.println", "models/Order.java": "public class Order"},
+		},
+		{
+			name:        "overwrites with -o",
+			options:     WriteOptions{Overwrite: true},
+			existing:    map[string]string{"models/User.java": handWritten},
+			wantActions: []string{"overwrite", "create"},
+			wantFiles:   map[string]string{"models/User.java": "//Add your code here"},
+		},
+		{
+			name:        "merges with -m and keeps method bodies",
+			options:     WriteOptions{Merge: true},
+			existing:    map[string]string{"models/User.java": handWritten},
+			wantActions: []string{"merge", "create"},
+			wantFiles:   map[string]string{"models/User.java": "System.out.println"},
+		},
+		{
+			name:        "dry run writes nothing",
+			options:     WriteOptions{DryRun: true, Overwrite: true},
+			existing:    map[string]string{"models/User.java": handWritten},
+			wantActions: []string{"overwrite", "create"},
+			wantFiles:   map[string]string{"models/User.java": "System.out.println", "models/Order.java": ""},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := output.NewMemorySink()
+			for path, content := range tt.existing {
+				sink.WriteFile(path, []byte(content))
+			}
+			writer := NewArtifactWriter(stubGenerator{}, tt.options)
+			classes := []*models.ClassModel{{Name: "User"}, {Name: "Order"}}
+
+			results := writer.WriteAll(classes, sink)
+			for i, r := range results {
+				if r.Err != nil {
+					t.Fatalf("%s: unexpected error %v", r.ClassName, r.Err)
+				}
+				if r.ClassName != classes[i].Name || r.Action != tt.wantActions[i] {
+					t.Errorf("result %d = %s %s, want %s %s", i, r.ClassName, r.Action, classes[i].Name, tt.wantActions[i])
+				}
+			}
+			for path, want := range tt.wantFiles {
+				data, err := sink.ReadFile(path)
+				switch {
+				case want == "" && err == nil:
+					t.Errorf("%s was written in dry-run mode", path)
+				case want != "" && err != nil:
+					t.Errorf("%s was not written: %v", path, err)
+				case want != "" && !strings.Contains(string(data), want):
+					t.Errorf("%s = %q, want it to contain %q", path, data, want)
+				}
+			}
+		})
+	}
+}
+
+func TestArtifactWriterReport(t *testing.T) {
+	sink := output.NewMemorySink()
+	writer := NewArtifactWriter(stubGenerator{}, WriteOptions{})
+	results := writer.WriteAll([]*models.ClassModel{{Name: "User"}, {Name: "Broken"}}, sink)
+	if results[1].Err == nil {
+		t.Fatalf("expected the generation of Broken to fail")
+	}
+
+	report := writer.WriteReport(results, sink)
+	if report.Err != nil || report.Action != "create" {
+		t.Fatalf("report = %s, %v; want create without error", report.Action, report.Err)
+	}
+	data, _ := sink.ReadFile(ReportFile)
+	want := "# Generation Report (Báo cáo tạo code)\n\n# User [.]\n\n# Broken [Failed (Thất bại)]\n- failed to generate code (không thể tạo code): boom\n\n"
+	if string(data) != want {
+		t.Errorf("Report.md = %q, want %q", data, want)
+	}
+
+	// Report.md luôn được tạo lại
+	if action := writer.PlannedAction(sink, ReportFile); action != "overwrite" {
+		t.Errorf("PlannedAction(Report.md) = %s, want overwrite", action)
+	}
+}
@@ -5,13 +5,20 @@ import (
 	"nUML/models"
 	"nUML/utils"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 )
 
+// reTypeName matches the simple type names inside a type expression (Map<String, Order> -> Map, String, Order).
+// reTypeName khớp các tên kiểu đơn giản bên trong một biểu thức kiểu (Map<String, Order> -> Map, String, Order).
+var reTypeName = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+
 // JavaGenerator implements CodeGenerator for Java.
 // JavaGenerator triển khai CodeGenerator cho Java.
 type JavaGenerator struct {
-	TargetPackage string // The target package name // Tên gói đích
+	TargetPackage string            // The target package name // Tên gói đích
+	classPackages map[string]string // Class name -> package, for cross-package imports // Tên lớp -> gói, dùng cho import giữa các gói
 }
 
 // NewJavaGenerator creates a new instance of JavaGenerator.
@@ -22,13 +29,38 @@ func NewJavaGenerator(targetPackage string) *JavaGenerator {
 	}
 }
 
+// RegisterClasses records the package of every class so that references across packages get an import.
+// RegisterClasses ghi nhận gói của mọi lớp để các tham chiếu giữa các gói được thêm import.
+func (jg *JavaGenerator) RegisterClasses(classes []*models.ClassModel) {
+	jg.classPackages = make(map[string]string)
+	for _, cls := range classes {
+		jg.classPackages[cls.Name] = jg.packageOf(cls)
+	}
+}
+
+// packageOf returns the package of a class: its own package (from the diagram) or the -f target package.
+// packageOf trả về gói của một lớp: gói riêng (từ biểu đồ) hoặc gói đích -f.
+func (jg *JavaGenerator) packageOf(cls *models.ClassModel) string {
+	if cls.Package != "" {
+		return cls.Package
+	}
+	return jg.TargetPackage
+}
+
 // Generate produces Java code for a ClassModel.
+// Classes with their own package are written to a matching directory tree (com/acme/billing/).
 // Generate tạo code Java cho một ClassModel.
+// Các lớp có gói riêng được ghi vào cây thư mục tương ứng (com/acme/billing/).
 func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, error) {
 	fileName := cls.Name + ".java"
+	if cls.Package != "" {
+		// -f đóng vai trò thư mục gốc của mã nguồn khi lớp có gói riêng
+		fileName = filepath.Join(filepath.FromSlash(strings.ReplaceAll(cls.Package, ".", "/")), fileName)
+	}
 	if jg.TargetPackage != "" {
 		fileName = filepath.Join(jg.TargetPackage, fileName)
 	}
+	pkg := jg.packageOf(cls)
 
 	utils.LogVerbose(fmt.Sprintf("Generating class: %s", cls.Name))
 
@@ -42,8 +74,8 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 
 	// Package Decl
 	// Khai báo Gói
-	if jg.TargetPackage != "" {
-		sb.WriteString("package " + jg.TargetPackage + ";\n\n")
+	if pkg != "" {
+		sb.WriteString("package " + pkg + ";\n\n")
 	}
 
 	// Imports
@@ -72,6 +104,21 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 		typeStr = "abstract class"
 	}
 
+	// Class annotations from shape metadata
+	// Chú thích lớp từ siêu dữ liệu hình
+	jg.writeAnnotations(&sb, "", cls.Annotations)
+
+	// Generic parameters: <T, ID extends Serializable>
+	// Tham số kiểu tổng quát: <T, ID extends Serializable>
+	typeParams := ""
+	if len(cls.TypeParameters) > 0 {
+		var params []string
+		for _, tp := range cls.TypeParameters {
+			params = append(params, tp.String())
+		}
+		typeParams = "<" + strings.Join(params, ", ") + ">"
+	}
+
 	if cls.Type == models.Record {
 		// Record Syntax: public record Name(Type field1, Type field2) { ... }
 		// Cú pháp Record: public record Name(Type field1, Type field2) { ... }
@@ -84,17 +131,37 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 				attrList = append(attrList, f.Name)
 			}
 		}
-		sb.WriteString(fmt.Sprintf("%s record %s(%s)", access, cls.Name, strings.Join(recordComponents, ", ")))
+		sb.WriteString(fmt.Sprintf("%s record %s%s(%s)", access, cls.Name, typeParams, strings.Join(recordComponents, ", ")))
 	} else {
-		sb.WriteString(fmt.Sprintf("%s %s %s", access, typeStr, cls.Name))
+		sb.WriteString(fmt.Sprintf("%s %s %s%s", access, typeStr, cls.Name, typeParams))
 	}
 
-	if cls.Extends != "" {
-		sb.WriteString(fmt.Sprintf(" extends %s", cls.Extends))
+	// <<exception>> / <<runtime exception>> without an explicit parent extends Exception / RuntimeException
+	// <<exception>> / <<runtime exception>> không có lớp cha rõ ràng sẽ kế thừa Exception / RuntimeException
+	// Supertypes keep the type arguments of their relationship (extends Base<User>)
+	// Các kiểu cha giữ đối số kiểu của quan hệ (extends Base<User>)
+	superClass := ""
+	var interfaces []string
+	for _, rel := range cls.Relationships {
+		switch {
+		case cls.Type == models.Interface && (rel.Kind == models.Generalization || rel.Kind == models.Realization):
+			// Giao diện có thể kế thừa nhiều giao diện: interface A extends B, C
+			superClass = strings.TrimPrefix(superClass+", "+rel.TargetType(), ", ")
+		case rel.Kind == models.Generalization && superClass == "":
+			superClass = rel.TargetType()
+		case rel.Kind == models.Realization:
+			interfaces = append(interfaces, rel.TargetType())
+		}
+	}
+	if superClass == "" && cls.Type != models.Interface {
+		superClass = cls.ExceptionBase()
+	}
+	if superClass != "" {
+		sb.WriteString(fmt.Sprintf(" extends %s", superClass))
 	}
 
-	if len(cls.Implements) > 0 {
-		sb.WriteString(fmt.Sprintf(" implements %s", strings.Join(cls.Implements, ", ")))
+	if len(interfaces) > 0 {
+		sb.WriteString(fmt.Sprintf(" implements %s", strings.Join(interfaces, ", ")))
 	}
 
 	sb.WriteString(" {\n\n")
@@ -110,7 +177,7 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 		for _, field := range cls.Fields {
 			// Heuristic for constants vs fields in Enum
 			// Quy tắc heuristic cho hằng số vs trường trong Enum
-			if strings.Contains(field.Original, ":") || strings.HasPrefix(field.Original, "-") || strings.HasPrefix(field.Original, "#") || strings.HasPrefix(field.Original, "+") {
+			if !field.IsEnumConstant() {
 				fields = append(fields, field)
 			} else {
 				// Also sanitize constant names
@@ -133,6 +200,7 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 			if field.IsFinal {
 				mod += " final"
 			}
+			jg.writeAnnotations(&sb, "    ", field.Annotations)
 			sb.WriteString(fmt.Sprintf("    %s %s %s;\n", mod, field.Type, field.Name))
 			attrList = append(attrList, field.Name)
 		}
@@ -148,6 +216,7 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 				if field.IsFinal {
 					mod += " final"
 				}
+				jg.writeAnnotations(&sb, "    ", field.Annotations)
 				sb.WriteString(fmt.Sprintf("    %s %s %s;\n", mod, field.Type, field.Name))
 				attrList = append(attrList, field.Name)
 			}
@@ -201,11 +270,18 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 				initStr = fmt.Sprintf(" = %s", field.InitialValue)
 			}
 
+			jg.writeAnnotations(&sb, "    ", field.Annotations)
 			sb.WriteString(fmt.Sprintf("    %s %s %s%s;\n", mod, field.Type, field.Name, initStr))
 		}
 		sb.WriteString("\n")
 	}
 
+	// Standard exception constructors, unless the diagram declares its own
+	// Các hàm khởi tạo ngoại lệ tiêu chuẩn, trừ khi biểu đồ tự khai báo
+	if cls.ExceptionBase() != "" && cls.Type == models.Class && !jg.hasConstructor(cls) {
+		constructorCount += jg.writeExceptionConstructors(&sb, cls.Name)
+	}
+
 	// 3. Methods
 	// 3. Các phương thức
 	for _, method := range cls.Methods {
@@ -221,6 +297,7 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 			constructorCount++
 		}
 
+		jg.writeAnnotations(&sb, "    ", method.Annotations)
 		if method.IsOverride {
 			sb.WriteString("    @Override\n")
 			inheritedList = append(inheritedList, method.Name)
@@ -251,36 +328,33 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 
 		// Params
 		// Các tham số
-		rawParams := strings.Split(method.Parameters, ",")
 		var javaParams []string
 		var paramNames []string
 
-		for _, p := range rawParams {
-			p = strings.TrimSpace(p)
-			if p == "" {
-				continue
-			}
-			parts := strings.Split(p, ":")
-			if len(parts) == 2 {
-				pName := strings.TrimSpace(parts[0])
-				pType := strings.TrimSpace(parts[1])
-				javaParams = append(javaParams, fmt.Sprintf("%s %s", pType, pName))
-				paramNames = append(paramNames, pName)
-			} else {
-				javaParams = append(javaParams, p)
+		for i, p := range method.Parameters {
+			pName := p.Name
+			if pName == "" {
+				// Biểu đồ chỉ ghi kiểu (add(int, int)): đặt tên theo vị trí
+				pName = fmt.Sprintf("arg%d", i)
 			}
+			javaParams = append(javaParams, fmt.Sprintf("%s %s", p.Type, pName))
+			paramNames = append(paramNames, pName)
 		}
 		paramStr := strings.Join(javaParams, ", ")
+		throwsStr := ""
+		if len(method.Throws) > 0 {
+			throwsStr = " throws " + strings.Join(method.Throws, ", ")
+		}
 
 		if isConstructor {
-			sb.WriteString(fmt.Sprintf("    %s %s(%s)", mod, method.Name, paramStr))
+			sb.WriteString(fmt.Sprintf("    %s %s(%s)%s", mod, method.Name, paramStr, throwsStr))
 		} else {
 			if mod == "default" {
-				sb.WriteString(fmt.Sprintf("    default %s %s(%s)", method.ReturnType, method.Name, paramStr))
+				sb.WriteString(fmt.Sprintf("    default %s %s(%s)%s", method.ReturnType, method.Name, paramStr, throwsStr))
 			} else if mod != "" {
-				sb.WriteString(fmt.Sprintf("    %s %s %s(%s)", strings.TrimSpace(mod), method.ReturnType, method.Name, paramStr))
+				sb.WriteString(fmt.Sprintf("    %s %s %s(%s)%s", strings.TrimSpace(mod), method.ReturnType, method.Name, paramStr, throwsStr))
 			} else {
-				sb.WriteString(fmt.Sprintf("    %s %s(%s)", method.ReturnType, method.Name, paramStr))
+				sb.WriteString(fmt.Sprintf("    %s %s(%s)%s", method.ReturnType, method.Name, paramStr, throwsStr))
 			}
 		}
 
@@ -292,7 +366,11 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 			// Auto-Body
 			// Tự động tạo thân hàm
 			if isConstructor {
-				for _, pName := range paramNames {
+				for i, pName := range paramNames {
+					// Tên theo vị trí (arg0) không ứng với trường nào của lớp
+					if method.Parameters[i].Name == "" {
+						continue
+					}
 					sb.WriteString(fmt.Sprintf("        this.%s = %s;\n", pName, pName))
 				}
 			} else if strings.HasPrefix(strings.ToLower(method.Name), "get") {
@@ -352,52 +430,69 @@ func (jg *JavaGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, e
 
 	// Generate Report
 	// Tạo báo cáo
-	var rpt strings.Builder
-	rpt.WriteString(fmt.Sprintf("# %s [.]\n", cls.Name))
-
-	// Attributes
-	// Thuộc tính
-	if len(attrList) > 0 {
-		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo các thuộc tính (Created attributes): {%s}\n", strings.Join(attrList, ", ")))
-	} else {
-		rpt.WriteString("- [.] Đã tạo các thuộc tính (Created attributes): {}\n")
+	rpt := &classReport{
+		attributes:   attrList,
+		constructors: constructorCount,
+		getters:      getterList,
+		setters:      setterList,
+		inherited:    inheritedList,
+		methods:      customMethodList,
 	}
 
-	// Constructors
-	// Hàm khởi tạo
-	if constructorCount > 0 {
-		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo {%d} constructor(s)\n", constructorCount))
-	}
+	return &GeneratedArtifact{
+		FileName:    fileName,
+		Content:     sb.String(),
+		ReportEntry: rpt.render(cls),
+	}, nil
+}
 
-	// Getters
-	if len(getterList) > 0 {
-		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo getter cho (Created getters for): { %s }\n", strings.Join(getterList, ", ")))
+// hasConstructor reports whether the diagram declares a constructor for the class.
+// hasConstructor cho biết biểu đồ có khai báo hàm khởi tạo cho lớp hay không.
+func (jg *JavaGenerator) hasConstructor(cls *models.ClassModel) bool {
+	for _, m := range cls.Methods {
+		if m.Name == cls.Name {
+			return true
+		}
 	}
+	return false
+}
 
-	// Setters
-	if len(setterList) > 0 {
-		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo setter cho (Created setters for): { %s }\n", strings.Join(setterList, ", ")))
+// writeExceptionConstructors writes the four standard constructors of an exception class and returns their count.
+// writeExceptionConstructors ghi bốn hàm khởi tạo tiêu chuẩn của một lớp ngoại lệ và trả về số lượng của chúng.
+func (jg *JavaGenerator) writeExceptionConstructors(sb *strings.Builder, name string) int {
+	constructors := []struct{ params, args string }{
+		{"", ""},
+		{"String message", "message"},
+		{"String message, Throwable cause", "message, cause"},
+		{"Throwable cause", "cause"},
 	}
-
-	// Inherited
-	// Kế thừa
-	if len(inheritedList) > 0 {
-		rpt.WriteString(fmt.Sprintf("- [.] Đã thừa kế (override) các phương thức (Overridden methods): { %s }\n", strings.Join(inheritedList, ", ")))
+	for _, c := range constructors {
+		sb.WriteString(fmt.Sprintf("    public %s(%s) {\n", name, c.params))
+		sb.WriteString(fmt.Sprintf("        super(%s);\n", c.args))
+		sb.WriteString("    }\n\n")
 	}
+	return len(constructors)
+}
 
-	// Custom Methods
-	// Phương thức tùy chỉnh
-	for _, cm := range customMethodList {
-		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo phương thức (Created method): %s\n", cm))
+// writeAnnotations writes one annotation per line with the given indentation.
+// writeAnnotations ghi mỗi chú thích trên một dòng với thụt lề cho trước.
+func (jg *JavaGenerator) writeAnnotations(sb *strings.Builder, indent string, annotations []string) {
+	for _, a := range annotations {
+		sb.WriteString(indent + a + "\n")
 	}
+}
 
-	rpt.WriteString("\n")
-
-	return &GeneratedArtifact{
-		FileName:    fileName,
-		Content:     sb.String(),
-		ReportEntry: rpt.String(),
-	}, nil
+// exceptionPackages maps common checked exceptions outside java.lang to their package.
+// exceptionPackages ánh xạ các ngoại lệ được kiểm tra phổ biến ngoài java.lang tới gói của chúng.
+var exceptionPackages = map[string]string{
+	"IOException":           "java.io",
+	"FileNotFoundException": "java.io",
+	"UncheckedIOException":  "java.io",
+	"SQLException":          "java.sql",
+	"TimeoutException":      "java.util.concurrent",
+	"ExecutionException":    "java.util.concurrent",
+	"URISyntaxException":    "java.net",
+	"MalformedURLException": "java.net",
 }
 
 // checkImports identifies necessary imports for the class.
@@ -408,25 +503,63 @@ func (jg *JavaGenerator) checkImports(cls *models.ClassModel) []string {
 	// Check types
 	// Kiểm tra các kiểu
 	checkType := func(t string) {
-		if strings.Contains(t, "List") || strings.Contains(t, "Map") || strings.Contains(t, "Set") {
+		if strings.Contains(t, "List") || strings.Contains(t, "Map") || strings.Contains(t, "Set") || strings.Contains(t, "Optional") {
 			imports["java.util.*"] = true
 		}
+		if strings.Contains(t, "Serializable") {
+			imports["java.io.Serializable"] = true
+		}
 		if strings.Contains(t, "LocalDate") || strings.Contains(t, "LocalTime") || strings.Contains(t, "Date") {
 			imports["java.time.*"] = true
 		}
 	}
 
+	// Cross-package references to classes of the diagram
+	// Tham chiếu giữa các gói tới các lớp của biểu đồ
+	ownPackage := jg.packageOf(cls)
+	checkClassRefs := func(t string) {
+		for _, ref := range reTypeName.FindAllString(t, -1) {
+			refPackage, ok := jg.classPackages[ref]
+			if ok && ref != cls.Name && refPackage != "" && refPackage != ownPackage {
+				imports[refPackage+"."+ref] = true
+			}
+		}
+	}
+
 	for _, f := range cls.Fields {
 		checkType(f.Type)
+		checkClassRefs(f.Type)
 	}
 	for _, m := range cls.Methods {
 		checkType(m.ReturnType)
-		checkType(m.Parameters)
+		checkClassRefs(m.ReturnType)
+		for _, p := range m.Parameters {
+			checkType(p.Type)
+			checkClassRefs(p.Type)
+		}
+		for _, t := range m.Throws {
+			checkClassRefs(t)
+			if pkg, ok := exceptionPackages[t]; ok {
+				imports[pkg+"."+t] = true
+			}
+		}
+	}
+	for _, rel := range cls.Relationships {
+		if rel.Kind == models.Generalization || rel.Kind == models.Realization {
+			checkClassRefs(rel.TargetType())
+		}
+	}
+	for _, tp := range cls.TypeParameters {
+		for _, b := range tp.Bounds {
+			checkType(b)
+			checkClassRefs(b)
+		}
 	}
 
 	var keys []string
 	for k := range imports {
 		keys = append(keys, k)
 	}
+	sort.Strings(keys)
 	return keys
 }
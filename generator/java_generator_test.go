@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"nUML/analyzer"
+	"strings"
+	"testing"
+)
+
+func TestJavaConstructorParameters(t *testing.T) {
+	source := "class Range {\n  - low : int\n  + Range(low : int)\n  + Range(int, int)\n}\n"
+	classes, err := analyzer.NewAnalyzerService(analyzer.OrderName).AnalyzePlantUML([]string{source})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jg := NewJavaGenerator("")
+	jg.RegisterClasses(classes)
+	artifact, err := jg.Generate(classes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tham số chỉ có kiểu được đặt tên theo vị trí và không được gán cho trường nào
+	for _, want := range []string{
+		"    public Range(int low) {\n        this.low = low;\n    }",
+		"    public Range(int arg0, int arg1) {\n    }",
+	} {
+		if !strings.Contains(artifact.Content, want) {
+			t.Errorf("Range.java does not contain %q:\n%s", want, artifact.Content)
+		}
+	}
+}
@@ -0,0 +1,771 @@
+package generator
+
+import (
+	"fmt"
+	"nUML/utils"
+	"regexp"
+	"strings"
+)
+
+var (
+	// reEnumConstants matches the constant list that opens an enum body: "RED, GREEN;" or "RED(1), GREEN(2);".
+	// reEnumConstants khớp danh sách hằng số mở đầu thân enum: "RED, GREEN;" hoặc "RED(1), GREEN(2);".
+	reEnumConstants = regexp.MustCompile(`^[A-Za-z_$][\w$]*\s*(\([^)]*\))?\s*[,;]`)
+	// reTypeKeyword matches the keyword of a nested type declaration.
+	// reTypeKeyword khớp từ khóa của một khai báo kiểu lồng nhau.
+	reTypeKeyword = regexp.MustCompile(`\b(class|interface|enum|record)\b`)
+	// reLastIdentifier matches the identifier at the end of a declaration head.
+	// reLastIdentifier khớp định danh ở cuối phần đầu của khai báo.
+	reLastIdentifier = regexp.MustCompile(`([A-Za-z_$][\w$]*)\s*(\[\s*\])*\s*$`)
+	// reInterfaceKeyword matches the keyword of an interface declaration.
+	// reInterfaceKeyword khớp từ khóa của một khai báo giao diện.
+	reInterfaceKeyword = regexp.MustCompile(`\binterface\b`)
+	// reEnumKeyword matches the keyword of an enum declaration.
+	// reEnumKeyword khớp từ khóa của một khai báo enum.
+	reEnumKeyword = regexp.MustCompile(`\benum\b`)
+	// javaModifiers are the modifiers that may precede the type of a field.
+	// javaModifiers là các từ khóa sửa đổi có thể đứng trước kiểu của một trường.
+	javaModifiers = map[string]bool{
+		"public": true, "protected": true, "private": true, "static": true, "final": true,
+		"transient": true, "volatile": true,
+	}
+)
+
+// javaMember is one top-level member of a class body as written in a .java file.
+// javaMember là một thành viên cấp cao nhất của thân lớp như được viết trong tệp .java.
+type javaMember struct {
+	leading     string   // Whitespace and comments before the member // Khoảng trắng và chú thích trước thành viên
+	annotations []string // Annotations such as @Override // Các chú thích như @Override
+	head        string   // Declaration up to the body, or the whole declaration when there is none // Khai báo đến trước thân, hoặc toàn bộ khai báo khi không có thân
+	body        string   // Method body with its braces, "" for fields and abstract methods // Thân phương thức kèm dấu ngoặc, "" với trường và phương thức trừu tượng
+	key         string   // Identity used to match members: "field:name", "name(int,String)"... // Định danh dùng để so khớp: "field:name", "name(int,String)"...
+	name        string   // Member name // Tên thành viên
+	isMethod    bool     // Method or constructor // Phương thức hoặc hàm khởi tạo
+}
+
+// text returns the member as written, without its leading trivia.
+// text trả về thành viên như đã viết, không kèm phần đứng trước.
+func (m javaMember) text() string {
+	var sb strings.Builder
+	indent := indentOf(m.leading)
+	for _, a := range m.annotations {
+		sb.WriteString(a + "\n" + indent)
+	}
+	sb.WriteString(m.head)
+	if m.body != "" {
+		sb.WriteString(" " + m.body)
+	}
+	return sb.String()
+}
+
+// javaFile is a .java file split into the parts the merger works on.
+// javaFile là một tệp .java được tách thành các phần mà bộ hợp nhất làm việc.
+type javaFile struct {
+	lead       string       // Comments before the package statement (license headers) // Chú thích trước câu lệnh package (tiêu đề giấy phép)
+	pkg        string       // package statement // Câu lệnh package
+	imports    []string     // import statements // Các câu lệnh import
+	declTrivia string       // Comments between the imports and the declaration (Javadoc) // Chú thích giữa các import và khai báo (Javadoc)
+	decl       string       // Annotations and declaration up to the opening brace // Chú thích và khai báo đến dấu ngoặc mở
+	members    []javaMember // Members of the class body // Các thành viên của thân lớp
+	tail       string       // Text between the last member and the closing brace // Văn bản giữa thành viên cuối và dấu ngoặc đóng
+	trailer    string       // Text after the closing brace // Văn bản sau dấu ngoặc đóng
+}
+
+// JavaMerger is responsible for regenerating a Java file without losing hand-written code.
+// JavaMerger chịu trách nhiệm tạo lại một tệp Java mà không làm mất mã viết tay.
+type JavaMerger struct{}
+
+// NewJavaMerger creates a new instance of JavaMerger.
+// NewJavaMerger tạo một phiên bản mới của JavaMerger.
+func NewJavaMerger() *JavaMerger {
+	return &JavaMerger{}
+}
+
+// Merge combines the file on disk with freshly generated code. Declarations (package, class header,
+// fields, method signatures) come from the diagram; method bodies, comments, extra imports and extra
+// members come from the existing file. Members missing from the diagram are kept and listed in the
+// returned notes, together with bodies that had to be dropped.
+// Merge kết hợp tệp trên đĩa với mã vừa được tạo. Các khai báo (package, tiêu đề lớp, trường, chữ ký
+// phương thức) lấy từ biểu đồ; thân phương thức, chú thích, import bổ sung và thành viên bổ sung lấy từ
+// tệp hiện có. Các thành viên không còn trong biểu đồ được giữ lại và liệt kê trong notes trả về, cùng
+// với các thân hàm buộc phải bỏ.
+func (jm *JavaMerger) Merge(existing, generated string) (string, []string, error) {
+	old, err := jm.parse(existing)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot parse existing file (không thể phân tích tệp hiện có): %v", err)
+	}
+	gen, err := jm.parse(generated)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot parse generated code (không thể phân tích mã được tạo): %v", err)
+	}
+
+	var notes []string
+	var sb strings.Builder
+
+	// 1. Preamble: diagram package and imports, plus the imports and comments added by hand
+	// 1. Phần mở đầu: package và import từ biểu đồ, cùng với import và chú thích được thêm tay
+	sb.WriteString(old.lead)
+	if gen.pkg != "" {
+		sb.WriteString(gen.pkg + "\n\n")
+	}
+	imports := append([]string(nil), gen.imports...)
+	for _, imp := range old.imports {
+		if !containsString(imports, imp) {
+			imports = append(imports, imp)
+		}
+	}
+	for _, imp := range imports {
+		sb.WriteString(imp + "\n")
+	}
+	if len(imports) > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(old.declTrivia)
+	decl, note := jm.mergeDecl(old.decl, gen.decl)
+	if note != "" {
+		notes = append(notes, note)
+	}
+	sb.WriteString(decl)
+
+	// 2. Members in diagram order; members only found on disk stay after their previous neighbour
+	// 2. Thành viên theo thứ tự của biểu đồ; thành viên chỉ có trên đĩa nằm sau thành viên đứng trước nó
+	match := jm.match(old.members, gen.members)
+	matchedOld := make(map[int]int) // chỉ số cũ -> chỉ số mới
+	for g, o := range match {
+		matchedOld[o] = g
+	}
+	var before []javaMember
+	after := make(map[int][]javaMember)
+	previous := -1
+	for o, m := range old.members {
+		if g, ok := matchedOld[o]; ok {
+			previous = g
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("Kept %s, no longer in the diagram (đã giữ, không còn trong biểu đồ)", jm.describe(m)))
+		if previous == -1 {
+			before = append(before, m)
+		} else {
+			after[previous] = append(after[previous], m)
+		}
+	}
+
+	for _, m := range before {
+		sb.WriteString(m.leading + m.text())
+	}
+	for g, gm := range gen.members {
+		o, ok := match[g]
+		if !ok {
+			sb.WriteString(gm.leading + gm.text())
+		} else {
+			merged, note := jm.mergeMember(old.members[o], gm)
+			if note != "" {
+				notes = append(notes, note)
+			}
+			sb.WriteString(merged)
+		}
+		for _, m := range after[g] {
+			sb.WriteString(m.leading + m.text())
+		}
+	}
+
+	// 3. Closing brace and anything written after the class
+	// 3. Dấu ngoặc đóng và mọi thứ được viết sau lớp
+	sb.WriteString(old.tail + "}")
+	trailer := gen.trailer
+	if strings.TrimSpace(old.trailer) != "" {
+		trailer = old.trailer
+	}
+	sb.WriteString(trailer)
+
+	return sb.String(), notes, nil
+}
+
+// match pairs generated members with existing ones: by signature first, then by name when a
+// method's parameters changed and the name is unambiguous. The result maps generated to existing indexes.
+// match ghép các thành viên được tạo với thành viên hiện có: trước hết theo chữ ký, sau đó theo tên khi
+// tham số của phương thức thay đổi và tên là duy nhất. Kết quả ánh xạ chỉ số mới sang chỉ số cũ.
+func (jm *JavaMerger) match(old, gen []javaMember) map[int]int {
+	match := make(map[int]int)
+	used := make(map[int]bool)
+	for g, gm := range gen {
+		for o, om := range old {
+			if !used[o] && om.key == gm.key {
+				match[g], used[o] = o, true
+				break
+			}
+		}
+	}
+
+	// countMethods đếm các phương thức chưa ghép có cùng tên
+	countMethods := func(members []javaMember, taken func(int) bool, name string) (int, int) {
+		count, index := 0, -1
+		for i, m := range members {
+			if m.isMethod && m.name == name && !taken(i) {
+				count, index = count+1, i
+			}
+		}
+		return count, index
+	}
+	for g, gm := range gen {
+		if _, ok := match[g]; ok || !gm.isMethod {
+			continue
+		}
+		genCount, _ := countMethods(gen, func(i int) bool { _, ok := match[i]; return ok }, gm.name)
+		oldCount, o := countMethods(old, func(i int) bool { return used[i] }, gm.name)
+		if genCount == 1 && oldCount == 1 {
+			match[g], used[o] = o, true
+		}
+	}
+	return match
+}
+
+// mergeMember keeps the comments, extra annotations and body of the existing member and takes the
+// declaration from the generated one. A note is returned when an existing body had to be dropped.
+// mergeMember giữ chú thích, chú thích bổ sung và thân của thành viên hiện có và lấy khai báo từ
+// thành viên được tạo. Trả về một ghi chú khi thân hiện có buộc phải bỏ.
+func (jm *JavaMerger) mergeMember(old, gen javaMember) (string, string) {
+	merged := gen
+	merged.leading = old.leading
+
+	// Chú thích của biểu đồ, cộng với chú thích được thêm tay (trừ @Override do bộ sinh mã quản lý)
+	for _, a := range old.annotations {
+		if annotationName(a) != "@Override" && !containsAnnotation(gen.annotations, a) {
+			merged.annotations = append(merged.annotations, a)
+		}
+	}
+
+	note := ""
+	switch {
+	case gen.isMethod && gen.body != "" && old.body != "":
+		merged.body = old.body
+	case gen.isMethod && gen.body == "" && old.body != "":
+		note = fmt.Sprintf("Dropped the body of %s, now abstract in the diagram (đã bỏ thân hàm, nay là trừu tượng)", jm.describe(old))
+	case !gen.isMethod && !strings.Contains(gen.head, "=") && strings.Contains(old.head, "="):
+		init := topLevelInitializer(old.head)
+		oldType, genType := fieldType(old.head), fieldType(gen.head)
+		switch {
+		case init == "":
+		case oldType != genType:
+			// Giá trị khởi tạo viết cho kiểu cũ có thể không còn biên dịch được: new ArrayList<>() cho Set<Item>
+			note = fmt.Sprintf("Dropped the initializer of %s, its type changed from %s to %s (đã bỏ giá trị khởi tạo vì kiểu đã thay đổi)", jm.describe(old), oldType, genType)
+		default:
+			// Giữ giá trị khởi tạo được viết tay: List<Item> items = new ArrayList<>();
+			merged.head = strings.TrimSuffix(strings.TrimSpace(gen.head), ";") + " =" + init
+		}
+	}
+	return merged.leading + merged.text(), note
+}
+
+// mergeDecl keeps the existing class declaration (annotations, comments, formatting) and takes the
+// modifiers, name and supertypes from the diagram. Supertypes added by hand to the implements list (or to
+// the extends list of an interface) are kept. A note is returned when the declaration was rewritten.
+// mergeDecl giữ khai báo lớp hiện có (chú thích, annotation, định dạng) và lấy từ khóa sửa đổi, tên và các
+// kiểu cha từ biểu đồ. Các kiểu cha được thêm tay vào danh sách implements (hoặc extends của giao diện)
+// được giữ lại. Trả về một ghi chú khi khai báo được viết lại.
+func (jm *JavaMerger) mergeDecl(oldDecl, genDecl string) (string, string) {
+	old, gen := splitDecl(oldDecl), splitDecl(genDecl)
+
+	var sb strings.Builder
+	sb.WriteString(old.prefix)
+	for _, a := range gen.annotations {
+		if !containsAnnotation(old.annotations, a) {
+			sb.WriteString(a + "\n")
+		}
+	}
+
+	note := ""
+	if strings.Join(strings.Fields(old.signature), " ") == strings.Join(strings.Fields(gen.signature), " ") {
+		sb.WriteString(old.signature)
+	} else {
+		sb.WriteString(gen.signature)
+		note = fmt.Sprintf("Updated the declaration to \"%s\" from the diagram (đã cập nhật khai báo theo biểu đồ)", strings.Join(strings.Fields(gen.signature), " "))
+	}
+
+	// Kiểu cha: lấy từ biểu đồ, cộng với các kiểu được thêm tay vào danh sách
+	extends, implements := gen.extends, gen.implements
+	if reInterfaceKeyword.MatchString(gen.signature) {
+		extends = appendMissing(extends, old.extends)
+	}
+	implements = appendMissing(implements, old.implements)
+	if equalStrings(extends, old.extends) && equalStrings(implements, old.implements) {
+		// Không có thay đổi: giữ nguyên định dạng gốc (danh sách trên nhiều dòng...)
+		sb.WriteString(old.clauses)
+		return sb.String(), note
+	}
+	if len(extends) > 0 {
+		sb.WriteString(" extends " + strings.Join(extends, ", "))
+	}
+	if len(implements) > 0 {
+		sb.WriteString(" implements " + strings.Join(implements, ", "))
+	}
+	sb.WriteString(" {")
+	return sb.String(), note
+}
+
+// describe returns a readable name for a member: "field name", "method run(int)".
+// describe trả về tên dễ đọc của một thành viên: "field name", "method run(int)".
+func (jm *JavaMerger) describe(m javaMember) string {
+	switch {
+	case m.isMethod:
+		return "method " + m.key
+	case strings.HasPrefix(m.key, "field:"):
+		return "field " + m.name
+	}
+	return strings.TrimSpace(strings.SplitN(m.head, "\n", 2)[0])
+}
+
+// parse splits Java source into preamble, class declaration, members and closing parts.
+// Only the first top-level type is split into members; anything after it is kept as trailer.
+// parse tách mã nguồn Java thành phần mở đầu, khai báo lớp, các thành viên và phần kết thúc.
+// Chỉ kiểu cấp cao nhất đầu tiên được tách thành thành viên; phần sau nó được giữ làm trailer.
+func (jm *JavaMerger) parse(src string) (*javaFile, error) {
+	file := &javaFile{}
+
+	// Dấu ngoặc mở thân lớp: dấu { đầu tiên ngoài ngoặc đơn (chú thích như @Table(indexes = {...}) có ngoặc nhọn)
+	open, depth := -1, 0
+	for i := 0; i < len(src) && open == -1; i++ {
+		if next := skipJavaTrivia(src, i); next != i {
+			i = next - 1
+			continue
+		}
+		switch src[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case '{':
+			if depth == 0 {
+				open = i
+			}
+		}
+	}
+	if open == -1 {
+		return nil, fmt.Errorf("no class body found (không tìm thấy thân lớp)")
+	}
+	jm.parsePreamble(src[:open+1], file)
+	isEnum := reEnumKeyword.MatchString(file.decl)
+
+	// Các thành viên cho đến dấu ngoặc đóng thân lớp
+	pos := open + 1
+	for {
+		start := pos
+		declStart := skipWhitespaceAndComments(src, pos)
+		if declStart >= len(src) {
+			return nil, fmt.Errorf("unterminated class body (thân lớp không được đóng)")
+		}
+		if src[declStart] == '}' {
+			file.tail = src[start:declStart]
+			file.trailer = src[declStart+1:]
+			return file, nil
+		}
+
+		member := javaMember{leading: src[start:declStart]}
+		i := declStart
+		// Chú thích (annotation) đứng trước khai báo
+		for i < len(src) && src[i] == '@' && !strings.HasPrefix(src[i:], "@interface") {
+			end := i + 1
+			for end < len(src) && (isIdentChar(src[end]) || src[end] == '.') {
+				end++
+			}
+			if end < len(src) && src[end] == '(' {
+				end = matchBracket(src, end, '(', ')') + 1
+			}
+			member.annotations = append(member.annotations, src[i:end])
+			i = skipWhitespaceAndComments(src, end)
+		}
+
+		end, bodyStart := jm.memberEnd(src, i)
+		if end == -1 {
+			return nil, fmt.Errorf("unterminated member near %q (thành viên không được đóng)", strings.TrimSpace(src[i:min(len(src), i+40)]))
+		}
+		if bodyStart != -1 {
+			member.head = strings.TrimRight(src[i:bodyStart], " \t\r\n")
+			member.body = src[bodyStart:end]
+		} else {
+			member.head = src[i:end]
+		}
+		jm.identify(&member, isEnum && len(file.members) == 0)
+		file.members = append(file.members, member)
+		pos = end
+	}
+}
+
+// parsePreamble reads the package, imports and comments before the class declaration.
+// parsePreamble đọc package, các import và chú thích trước khai báo lớp.
+func (jm *JavaMerger) parsePreamble(text string, file *javaFile) {
+	pos := 0
+	for {
+		next := skipWhitespaceAndComments(text, pos)
+		trivia := text[pos:next]
+		rest := text[next:]
+		isPackage := strings.HasPrefix(rest, "package ")
+		isImport := strings.HasPrefix(rest, "import ")
+		if !isPackage && !isImport {
+			// Chú thích ngay trước khai báo (Javadoc), không tính các dòng trống đầu
+			comment := strings.TrimLeft(trivia, " \t\r\n")
+			if file.pkg == "" && len(file.imports) == 0 {
+				file.lead = comment
+			} else if comment != "" {
+				file.declTrivia = comment
+			}
+			file.decl = rest
+			return
+		}
+		end := strings.IndexByte(rest, ';')
+		if end == -1 {
+			file.decl = rest
+			return
+		}
+		if file.pkg == "" && len(file.imports) == 0 {
+			file.lead = strings.TrimLeft(trivia, " \t\r\n")
+		}
+		statement := rest[:end+1]
+		if isPackage {
+			file.pkg = statement
+		} else {
+			file.imports = append(file.imports, statement)
+		}
+		pos = next + end + 1
+	}
+}
+
+// memberEnd returns the end of the member starting at i and the start of its body ({), or -1 without a body.
+// Fields with an initializer ("= {1, 2};", anonymous classes) end at their semicolon.
+// memberEnd trả về vị trí kết thúc của thành viên bắt đầu tại i và vị trí bắt đầu thân ({), hoặc -1 khi không có thân.
+// Trường có giá trị khởi tạo ("= {1, 2};", lớp ẩn danh) kết thúc tại dấu chấm phẩy.
+func (jm *JavaMerger) memberEnd(src string, i int) (int, int) {
+	depth := 0
+	hasInitializer := false
+	for ; i < len(src); i++ {
+		if next := skipJavaTrivia(src, i); next != i {
+			i = next - 1
+			continue
+		}
+		switch c := src[i]; {
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth > 0:
+		case c == '=':
+			hasInitializer = true
+		case c == ';':
+			return i + 1, -1
+		case c == '{':
+			close := matchBracket(src, i, '{', '}')
+			if close == -1 {
+				return -1, -1
+			}
+			if !hasInitializer {
+				return close + 1, i
+			}
+			i = close
+		}
+	}
+	return -1, -1
+}
+
+// identify fills the key and name of a member from its head.
+// identify điền khóa và tên của thành viên từ phần đầu của nó.
+func (jm *JavaMerger) identify(m *javaMember, mayBeConstants bool) {
+	head := strings.TrimSpace(m.head)
+	if mayBeConstants && reEnumConstants.MatchString(head) {
+		m.key, m.name = "#constants", "constants"
+		return
+	}
+
+	paren := strings.IndexByte(head, '(')
+	eq := strings.IndexByte(head, '=')
+	if paren != -1 && (eq == -1 || paren < eq) && !reTypeKeyword.MatchString(head[:paren]) {
+		// Phương thức hoặc hàm khởi tạo: khóa là tên và các kiểu tham số
+		m.isMethod = true
+		if id := reLastIdentifier.FindStringSubmatch(head[:paren]); id != nil {
+			m.name = id[1]
+		}
+		close := matchBracket(head, paren, '(', ')')
+		if close == -1 {
+			close = len(head)
+		}
+		var types []string
+		for _, p := range utils.SplitTopLevel(head[paren+1:close], ',') {
+			var words []string
+			for _, w := range strings.Fields(p) {
+				if w != "final" && !strings.HasPrefix(w, "@") {
+					words = append(words, w)
+				}
+			}
+			if len(words) > 1 {
+				types = append(types, strings.Join(words[:len(words)-1], ""))
+			} else if len(words) == 1 {
+				types = append(types, words[0])
+			}
+		}
+		m.key = m.name + "(" + strings.Join(types, ",") + ")"
+		return
+	}
+
+	if strings.HasSuffix(head, ";") && !reTypeKeyword.MatchString(head) {
+		left := strings.TrimSuffix(head, ";")
+		if eq != -1 {
+			left = head[:eq]
+		}
+		if id := reLastIdentifier.FindStringSubmatch(left); id != nil {
+			m.name = id[1]
+		}
+		m.key = "field:" + m.name
+		return
+	}
+
+	// Lớp lồng nhau, khối khởi tạo...: khóa là dòng đầu đã chuẩn hóa khoảng trắng
+	m.key = "other:" + strings.Join(strings.Fields(strings.SplitN(head, "\n", 2)[0]), " ")
+}
+
+// javaDecl is a class declaration split into its parts.
+// javaDecl là một khai báo lớp được tách thành các phần.
+type javaDecl struct {
+	prefix      string   // Annotations and comments before the modifiers, as written // Annotation và chú thích trước từ khóa sửa đổi, như đã viết
+	annotations []string // Annotations found in the prefix // Các annotation trong prefix
+	signature   string   // Modifiers, keyword, name and type parameters // Từ khóa sửa đổi, từ khóa kiểu, tên và tham số kiểu
+	extends     []string // Types of the extends clause // Các kiểu của mệnh đề extends
+	implements  []string // Types of the implements clause // Các kiểu của mệnh đề implements
+	clauses     string   // Text from the end of the signature to the opening brace, as written // Văn bản từ cuối chữ ký đến dấu ngoặc mở, như đã viết
+}
+
+// splitDecl splits a declaration ending with "{" into annotations, signature and supertype clauses.
+// splitDecl tách một khai báo kết thúc bằng "{" thành annotation, chữ ký và các mệnh đề kiểu cha.
+func splitDecl(decl string) javaDecl {
+	var d javaDecl
+	i := skipWhitespaceAndComments(decl, 0)
+	for i < len(decl) && decl[i] == '@' && !strings.HasPrefix(decl[i:], "@interface") {
+		end := i + 1
+		for end < len(decl) && (isIdentChar(decl[end]) || decl[end] == '.') {
+			end++
+		}
+		if end < len(decl) && decl[end] == '(' {
+			if close := matchBracket(decl, end, '(', ')'); close != -1 {
+				end = close + 1
+			}
+		}
+		d.annotations = append(d.annotations, decl[i:end])
+		i = skipWhitespaceAndComments(decl, end)
+	}
+	d.prefix = decl[:i]
+	rest := strings.TrimSuffix(decl[i:], "{")
+
+	// Các mệnh đề bắt đầu tại extends/implements/permits ngoài dấu <> (<T extends Comparable<T>>)
+	var bounds []int
+	depth := 0
+	for j := 0; j < len(rest); j++ {
+		switch c := rest[j]; {
+		case c == '<':
+			depth++
+		case c == '>':
+			depth--
+		case depth == 0 && (j == 0 || !isIdentChar(rest[j-1])):
+			for _, kw := range []string{"extends", "implements", "permits"} {
+				if strings.HasPrefix(rest[j:], kw) && (j+len(kw) == len(rest) || !isIdentChar(rest[j+len(kw)])) {
+					bounds = append(bounds, j)
+				}
+			}
+		}
+	}
+	end := len(strings.TrimRight(rest, " \t\r\n"))
+	if len(bounds) > 0 {
+		end = len(strings.TrimRight(rest[:bounds[0]], " \t\r\n"))
+	}
+	d.signature = rest[:end]
+	d.clauses = decl[i+end:]
+
+	for k, start := range bounds {
+		stop := len(rest)
+		if k+1 < len(bounds) {
+			stop = bounds[k+1]
+		}
+		clause := rest[start:stop]
+		kw := clause[:strings.IndexAny(clause+" ", " \t\r\n")]
+		var types []string
+		for _, t := range utils.SplitTopLevel(strings.TrimPrefix(clause, kw), ',') {
+			if t = strings.Join(strings.Fields(t), " "); t != "" {
+				types = append(types, t)
+			}
+		}
+		switch kw {
+		case "extends":
+			d.extends = types
+		case "implements":
+			d.implements = types
+		}
+	}
+	return d
+}
+
+// fieldType returns the type of a field declaration without modifiers, annotations and spaces.
+// fieldType trả về kiểu của khai báo trường, không kèm từ khóa sửa đổi, annotation và khoảng trắng.
+func fieldType(head string) string {
+	left := strings.TrimSuffix(strings.TrimSpace(head), ";")
+	if parts := utils.SplitTopLevel(left, '='); len(parts) > 1 {
+		left = parts[0]
+	}
+	left = strings.TrimSpace(left)
+	if id := reLastIdentifier.FindStringIndex(left); id != nil {
+		left = left[:id[0]]
+	}
+	var words []string
+	for _, w := range strings.Fields(left) {
+		if !javaModifiers[w] && !strings.HasPrefix(w, "@") {
+			words = append(words, w)
+		}
+	}
+	return strings.Join(words, "")
+}
+
+// appendMissing appends the items of extra that list does not contain yet.
+// appendMissing thêm vào list các phần tử của extra mà list chưa có.
+func appendMissing(list, extra []string) []string {
+	for _, item := range extra {
+		if !containsString(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// equalStrings reports whether two lists hold the same strings in the same order.
+// equalStrings cho biết hai danh sách có cùng các chuỗi theo cùng thứ tự hay không.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// skipJavaTrivia returns the index after a comment, string or character literal starting at i, or i.
+// skipJavaTrivia trả về vị trí sau chú thích, chuỗi hoặc ký tự bắt đầu tại i, hoặc chính i.
+func skipJavaTrivia(src string, i int) int {
+	switch {
+	case strings.HasPrefix(src[i:], "//"):
+		if end := strings.IndexByte(src[i:], '\n'); end != -1 {
+			return i + end + 1
+		}
+		return len(src)
+	case strings.HasPrefix(src[i:], "/*"):
+		if end := strings.Index(src[i+2:], "*/"); end != -1 {
+			return i + 2 + end + 2
+		}
+		return len(src)
+	case strings.HasPrefix(src[i:], `"""`):
+		if end := strings.Index(src[i+3:], `"""`); end != -1 {
+			return i + 3 + end + 3
+		}
+		return len(src)
+	case src[i] == '"' || src[i] == '\'':
+		quote := src[i]
+		for j := i + 1; j < len(src); j++ {
+			switch src[j] {
+			case '\\':
+				j++
+			case quote:
+				return j + 1
+			}
+		}
+		return len(src)
+	}
+	return i
+}
+
+// skipWhitespaceAndComments returns the index of the next character that is neither whitespace nor a comment.
+// skipWhitespaceAndComments trả về vị trí của ký tự tiếp theo không phải khoảng trắng hay chú thích.
+func skipWhitespaceAndComments(src string, i int) int {
+	for i < len(src) {
+		switch {
+		case src[i] == ' ' || src[i] == '\t' || src[i] == '\r' || src[i] == '\n':
+			i++
+		case strings.HasPrefix(src[i:], "//") || strings.HasPrefix(src[i:], "/*"):
+			i = skipJavaTrivia(src, i)
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// matchBracket returns the index of the bracket closing the one at i, skipping comments and literals, or -1.
+// matchBracket trả về vị trí của dấu ngoặc đóng tương ứng với dấu ngoặc tại i, bỏ qua chú thích và hằng, hoặc -1.
+func matchBracket(src string, i int, open, close byte) int {
+	depth := 0
+	for ; i < len(src); i++ {
+		if next := skipJavaTrivia(src, i); next != i {
+			i = next - 1
+			continue
+		}
+		switch src[i] {
+		case open:
+			depth++
+		case close:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// topLevelInitializer returns the text after the top-level "=" of a field declaration, including the semicolon.
+// topLevelInitializer trả về phần văn bản sau dấu "=" cấp cao nhất của khai báo trường, kèm dấu chấm phẩy.
+func topLevelInitializer(head string) string {
+	parts := utils.SplitTopLevel(head, '=')
+	if len(parts) < 2 {
+		return ""
+	}
+	return strings.Join(parts[1:], "=")
+}
+
+// indentOf returns the indentation at the end of a leading trivia block ("\n\n    " -> "    ").
+// indentOf trả về phần thụt lề ở cuối khối đứng trước ("\n\n    " -> "    ").
+func indentOf(leading string) string {
+	return leading[strings.LastIndexByte(leading, '\n')+1:]
+}
+
+// annotationName returns the name of an annotation without its arguments: @Column(name="x") -> @Column.
+// annotationName trả về tên của chú thích không kèm đối số: @Column(name="x") -> @Column.
+func annotationName(a string) string {
+	if idx := strings.IndexByte(a, '('); idx != -1 {
+		return a[:idx]
+	}
+	return a
+}
+
+// containsAnnotation reports whether a list holds an annotation with the same name.
+// containsAnnotation cho biết danh sách có chứa chú thích cùng tên hay không.
+func containsAnnotation(list []string, a string) bool {
+	for _, item := range list {
+		if annotationName(item) == annotationName(a) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether a list holds a string.
+// containsString cho biết danh sách có chứa một chuỗi hay không.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdentChar reports whether a byte may appear in a Java identifier.
+// isIdentChar cho biết một byte có thể xuất hiện trong định danh Java hay không.
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
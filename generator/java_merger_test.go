@@ -0,0 +1,114 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJavaMergerMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		existing  string
+		generated string
+		want      []string // Các đoạn phải có trong kết quả
+		notWant   []string // Các đoạn không được có trong kết quả
+		notes     []string // Các đoạn phải có trong ghi chú, theo thứ tự
+	}{
+		{
+			name:      "keeps method bodies and hand-written imports",
+			existing:  "import java.io.File;\n\npublic class User {\n\n    public void run() {\n        System.out.println(\"hi\");\n    }\n\n}\n",
+			generated: "import java.util.*;\n\npublic class User {\n\n    public void run() {\n        //Add your code here\n    }\n\n}\n",
+			want:      []string{"import java.util.*;\nimport java.io.File;\n", "System.out.println(\"hi\");"},
+			notWant:   []string{"//Add your code here"},
+		},
+		{
+			name:      "keeps members removed from the diagram after their neighbour",
+			existing:  "public class User {\n\n    private String name;\n\n    private int cache;\n\n}\n",
+			generated: "public class User {\n\n    private String name;\n\n    private String email;\n\n}\n",
+			want:      []string{"private String name;\n\n    private int cache;\n\n    private String email;"},
+			notes:     []string{"Kept field cache"},
+		},
+		{
+			name:      "matches a method whose parameters changed by name",
+			existing:  "public class User {\n\n    public void rename(String name) {\n        this.name = name.trim();\n    }\n\n}\n",
+			generated: "public class User {\n\n    public void rename(String first, String last) {\n        //Add your code here\n    }\n\n}\n",
+			want:      []string{"public void rename(String first, String last) {\n        this.name = name.trim();"},
+		},
+		{
+			name:      "drops the body of a method that became abstract",
+			existing:  "public abstract class Shape {\n\n    public double area() {\n        return 0;\n    }\n\n}\n",
+			generated: "public abstract class Shape {\n\n    public abstract double area();\n\n}\n",
+			want:      []string{"public abstract double area();"},
+			notWant:   []string{"return 0;"},
+			notes:     []string{"Dropped the body of method area()"},
+		},
+		{
+			name:      "keeps the initializer of a field whose type did not change",
+			existing:  "public class Order {\n\n    private List<Item> items = new ArrayList<>();\n\n}\n",
+			generated: "public class Order {\n\n    private final List<Item> items;\n\n}\n",
+			want:      []string{"private final List<Item> items = new ArrayList<>();"},
+		},
+		{
+			name:      "drops the initializer of a field whose type changed",
+			existing:  "public class Order {\n\n    private List<Item> items = new ArrayList<>();\n\n}\n",
+			generated: "public class Order {\n\n    private Set<Item> items;\n\n}\n",
+			want:      []string{"private Set<Item> items;"},
+			notWant:   []string{"new ArrayList"},
+			notes:     []string{"Dropped the initializer of field items, its type changed from List<Item> to Set<Item>"},
+		},
+		{
+			name:      "keeps the hand-written declaration, comments and tail",
+			existing:  "/** Users. */\n@Entity\npublic class User   implements Serializable {\n\n    private String name;\n\n    // end of members\n}\n",
+			generated: "public class User {\n\n    private String name;\n\n}\n",
+			want:      []string{"/** Users. */\n@Entity\npublic class User   implements Serializable {", "    // end of members\n}"},
+		},
+		{
+			name:      "updates supertypes from the diagram and keeps added interfaces",
+			existing:  "public class User implements Serializable {\n\n}\n",
+			generated: "public class User extends Person implements Comparable<User> {\n\n}\n",
+			want:      []string{"public class User extends Person implements Comparable<User>, Serializable {"},
+		},
+		{
+			name:      "rewrites a renamed declaration",
+			existing:  "public class User {\n\n}\n",
+			generated: "public abstract class User {\n\n}\n",
+			want:      []string{"public abstract class User {"},
+			notes:     []string{"Updated the declaration to \"public abstract class User\""},
+		},
+	}
+
+	merger := NewJavaMerger()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, notes, err := merger.Merge(tt.existing, tt.generated)
+			if err != nil {
+				t.Fatalf("Merge failed: %v", err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("merged file does not contain %q:\n%s", w, got)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("merged file contains %q:\n%s", w, got)
+				}
+			}
+			if len(notes) != len(tt.notes) {
+				t.Fatalf("notes = %q, want %d notes", notes, len(tt.notes))
+			}
+			for i, n := range tt.notes {
+				if !strings.Contains(notes[i], n) {
+					t.Errorf("note %d = %q, want it to contain %q", i, notes[i], n)
+				}
+			}
+		})
+	}
+}
+
+func TestJavaMergerMergeUnparsable(t *testing.T) {
+	merger := NewJavaMerger()
+	if _, _, err := merger.Merge("public class User {\n", "public class User {\n}\n"); err == nil {
+		t.Errorf("Merge accepted an existing file without its closing brace")
+	}
+}
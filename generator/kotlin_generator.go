@@ -0,0 +1,878 @@
+package generator
+
+import (
+	"fmt"
+	"nUML/models"
+	"nUML/utils"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// kotlinTypes maps Java type names to their Kotlin equivalents.
+	// kotlinTypes ánh xạ tên kiểu Java sang kiểu Kotlin tương ứng.
+	kotlinTypes = map[string]string{
+		"int": "Int", "Integer": "Int", "long": "Long", "short": "Short", "byte": "Byte",
+		"double": "Double", "float": "Float", "boolean": "Boolean", "char": "Char", "Character": "Char",
+		"void": "Unit", "Void": "Unit", "Object": "Any", "java.lang.Object": "Any", "java.lang.String": "String",
+	}
+	// kotlinArrays maps primitive element types to the specialised Kotlin arrays (int[] -> IntArray).
+	// kotlinArrays ánh xạ kiểu phần tử nguyên thủy sang mảng chuyên biệt của Kotlin (int[] -> IntArray).
+	kotlinArrays = map[string]string{
+		"int": "IntArray", "long": "LongArray", "short": "ShortArray", "byte": "ByteArray",
+		"double": "DoubleArray", "float": "FloatArray", "boolean": "BooleanArray", "char": "CharArray",
+	}
+	// kotlinDefaults holds the default value of the Kotlin types that have an obvious one.
+	// kotlinDefaults chứa giá trị mặc định của các kiểu Kotlin có giá trị hiển nhiên.
+	kotlinDefaults = map[string]string{
+		"Int": "0", "Short": "0", "Byte": "0", "Long": "0L", "Double": "0.0", "Float": "0.0f",
+		"Boolean": "false", "Char": "'\\u0000'", "String": "\"\"",
+	}
+	// kotlinKeywords are the hard keywords that must be escaped with backticks when used as names.
+	// kotlinKeywords là các từ khóa cứng phải được bọc bằng dấu backtick khi dùng làm tên.
+	kotlinKeywords = map[string]bool{
+		"as": true, "fun": true, "in": true, "is": true, "object": true, "typealias": true,
+		"typeof": true, "val": true, "var": true, "when": true,
+	}
+	// reTypeMultiplicity matches a UML multiplicity written after a type: String [0..1].
+	// reTypeMultiplicity khớp bội số UML được viết sau kiểu: String [0..1].
+	reTypeMultiplicity = regexp.MustCompile(`\s*\[\s*([\d*n]+(\s*\.\.\s*[\d*n]+)?)\s*\]$`)
+)
+
+// KotlinGenerator implements CodeGenerator for Kotlin.
+// KotlinGenerator triển khai CodeGenerator cho Kotlin.
+type KotlinGenerator struct {
+	TargetPackage string                        // The target package name // Tên gói đích
+	classPackages map[string]string             // Class name -> package, for cross-package imports // Tên lớp -> gói, dùng cho import giữa các gói
+	openClasses   map[string]bool               // Classes extended by another class, generated as open // Các lớp được lớp khác kế thừa, được tạo là open
+	classes       map[string]*models.ClassModel // Class name -> class, to find overridden methods // Tên lớp -> lớp, để tìm phương thức bị ghi đè
+}
+
+// NewKotlinGenerator creates a new instance of KotlinGenerator.
+// NewKotlinGenerator tạo một phiên bản mới của KotlinGenerator.
+func NewKotlinGenerator(targetPackage string) *KotlinGenerator {
+	return &KotlinGenerator{
+		TargetPackage: targetPackage,
+	}
+}
+
+// RegisterClasses records the package of every class and the classes that have subclasses
+// (Kotlin classes are final unless declared open).
+// RegisterClasses ghi nhận gói của mọi lớp và các lớp có lớp con
+// (lớp Kotlin là final trừ khi được khai báo open).
+func (kg *KotlinGenerator) RegisterClasses(classes []*models.ClassModel) {
+	kg.classPackages = make(map[string]string)
+	kg.openClasses = make(map[string]bool)
+	kg.classes = make(map[string]*models.ClassModel)
+	for _, cls := range classes {
+		kg.classPackages[cls.Name] = kg.packageOf(cls)
+		kg.classes[cls.Name] = cls
+		for _, rel := range cls.Relationships {
+			if rel.Kind == models.Generalization && cls.Type != models.Interface {
+				kg.openClasses[rel.Target] = true
+			}
+		}
+	}
+}
+
+// packageOf returns the package of a class: its own package (from the diagram) or the -f target package.
+// packageOf trả về gói của một lớp: gói riêng (từ biểu đồ) hoặc gói đích -f.
+func (kg *KotlinGenerator) packageOf(cls *models.ClassModel) string {
+	if cls.Package != "" {
+		return cls.Package
+	}
+	return kg.TargetPackage
+}
+
+// Generate produces Kotlin code for a ClassModel. Records and classes holding only fields become data
+// classes, static members go to a companion object and nullability comes from "?" or a 0..1 multiplicity.
+// Generate tạo code Kotlin cho một ClassModel. Record và lớp chỉ có trường trở thành data class, thành viên
+// tĩnh nằm trong companion object và khả năng null lấy từ "?" hoặc bội số 0..1.
+func (kg *KotlinGenerator) Generate(cls *models.ClassModel) (*GeneratedArtifact, error) {
+	fileName := cls.Name + ".kt"
+	if cls.Package != "" {
+		// -f đóng vai trò thư mục gốc của mã nguồn khi lớp có gói riêng
+		fileName = filepath.Join(filepath.FromSlash(strings.ReplaceAll(cls.Package, ".", "/")), fileName)
+	}
+	if kg.TargetPackage != "" {
+		fileName = filepath.Join(kg.TargetPackage, fileName)
+	}
+	pkg := kg.packageOf(cls)
+
+	utils.LogVerbose(fmt.Sprintf("Generating Kotlin class: %s", cls.Name))
+
+	var sb strings.Builder
+	rpt := &classReport{}
+
+	// Package Decl
+	// Khai báo Gói
+	if pkg != "" {
+		sb.WriteString("package " + pkg + "\n\n")
+	}
+
+	// Imports
+	// Nhập khẩu (Imports)
+	imports := kg.checkImports(cls)
+	for _, imp := range imports {
+		sb.WriteString("import " + imp + "\n")
+	}
+	if len(imports) > 0 {
+		sb.WriteString("\n")
+	}
+
+	needGettersSetters := kg.hasPlaceholder(cls)
+	isData := kg.isDataClass(cls)
+	hasConstructors := cls.ExceptionBase() != "" && cls.Type == models.Class && !kg.hasConstructor(cls)
+	for _, m := range cls.Methods {
+		hasConstructors = hasConstructors || m.Name == cls.Name
+	}
+
+	// 1. Declaration
+	// 1. Khai báo
+	kg.writeAnnotations(&sb, "", cls.Annotations)
+	typeParams, whereClause := kg.typeParameters(cls)
+
+	switch {
+	case isData:
+		sb.WriteString("data class " + cls.Name + typeParams + "(\n")
+		var components []string
+		for _, f := range cls.Fields {
+			if f.IsStatic || kg.isPlaceholder(f.Original) {
+				continue
+			}
+			// Record là bất biến; các trường của lớp chỉ có dữ liệu giữ nguyên khả năng thay đổi như Java
+			keyword := "var"
+			if cls.Type == models.Record || f.IsFinal {
+				keyword = "val"
+			}
+			var line strings.Builder
+			kg.writeAnnotations(&line, "    ", f.Annotations)
+			ktType, nullable := kg.kotlinType(f.Type, f.Multiplicity, f.InitialValue)
+			line.WriteString(fmt.Sprintf("    %s %s: %s", keyword, kotlinName(f.Name), ktType))
+			if f.InitialValue != "" {
+				line.WriteString(" = " + kotlinExpression(f.InitialValue))
+			} else if nullable {
+				line.WriteString(" = null")
+			}
+			components = append(components, line.String())
+			rpt.attributes = append(rpt.attributes, f.Name)
+		}
+		sb.WriteString(strings.Join(components, ",\n") + "\n)")
+	case cls.Type == models.Interface:
+		sb.WriteString("interface " + cls.Name + typeParams)
+	case cls.Type == models.Enum:
+		sb.WriteString("enum class " + cls.Name + typeParams)
+	case cls.Type == models.Abstract:
+		sb.WriteString("abstract class " + cls.Name + typeParams)
+	case kg.openClasses[cls.Name]:
+		sb.WriteString("open class " + cls.Name + typeParams)
+	default:
+		sb.WriteString("class " + cls.Name + typeParams)
+	}
+
+	// Supertypes: the superclass constructor is called unless secondary constructors delegate to it
+	// Kiểu cha: hàm khởi tạo của lớp cha được gọi trừ khi các hàm khởi tạo phụ ủy quyền cho nó
+	var supertypes []string
+	superClass := ""
+	for _, rel := range cls.Relationships {
+		if rel.Kind != models.Generalization && rel.Kind != models.Realization {
+			continue
+		}
+		target, _ := kg.kotlinType(rel.TargetType(), "", "")
+		switch {
+		case cls.Type == models.Interface || rel.Kind == models.Realization:
+			supertypes = append(supertypes, target)
+		case superClass == "":
+			superClass = target
+		}
+	}
+	if superClass == "" && cls.Type != models.Interface {
+		superClass = cls.ExceptionBase()
+	}
+	if superClass != "" {
+		if !hasConstructors {
+			superClass += "()"
+		}
+		supertypes = append([]string{superClass}, supertypes...)
+	}
+	if len(supertypes) > 0 {
+		sb.WriteString(" : " + strings.Join(supertypes, ", "))
+	}
+	sb.WriteString(whereClause)
+
+	// 2. Body
+	// 2. Thân
+	var body strings.Builder
+	var companion strings.Builder
+
+	switch cls.Type {
+	case models.Enum:
+		var constants []string
+		for _, field := range cls.Fields {
+			if field.IsEnumConstant() {
+				constants = append(constants, utils.SanitizeName(field.Name))
+			}
+		}
+		rpt.attributes = append(rpt.attributes, constants...)
+		if len(constants) > 0 {
+			body.WriteString("    " + strings.Join(constants, ", "))
+			// Dấu chấm phẩy chỉ cần khi có thành viên theo sau
+			if len(constants) < len(cls.Fields) || len(cls.Methods) > 0 {
+				body.WriteString(";")
+			}
+			body.WriteString("\n\n")
+		}
+		for _, field := range cls.Fields {
+			if !field.IsEnumConstant() {
+				kg.writeProperty(&body, &companion, field, false)
+				rpt.attributes = append(rpt.attributes, field.Name)
+			}
+		}
+	case models.Interface:
+		// Giao diện không có trạng thái; như bộ sinh Java, các trường của giao diện không được tạo
+	default:
+		if !isData {
+			for _, field := range cls.Fields {
+				if kg.isPlaceholder(field.Original) || strings.Contains(field.Name, "(") {
+					continue
+				}
+				kg.writeProperty(&body, &companion, field, needGettersSetters)
+				rpt.attributes = append(rpt.attributes, field.Name)
+				if needGettersSetters && !field.IsStatic {
+					// Thuộc tính Kotlin công khai đã có getter/setter
+					rpt.getters = append(rpt.getters, field.Name)
+					if !field.IsFinal {
+						rpt.setters = append(rpt.setters, field.Name)
+					}
+				}
+			}
+		} else {
+			for _, field := range cls.Fields {
+				if field.IsStatic {
+					kg.writeProperty(&body, &companion, field, false)
+					rpt.attributes = append(rpt.attributes, field.Name)
+				}
+			}
+		}
+	}
+	if body.Len() > 0 && !strings.HasSuffix(body.String(), "\n\n") {
+		body.WriteString("\n")
+	}
+
+	// Standard exception constructors, unless the diagram declares its own
+	// Các hàm khởi tạo ngoại lệ tiêu chuẩn, trừ khi biểu đồ tự khai báo
+	if cls.ExceptionBase() != "" && cls.Type == models.Class && !kg.hasConstructor(cls) {
+		rpt.constructors += kg.writeExceptionConstructors(&body)
+	}
+
+	// 3. Methods
+	// 3. Các phương thức
+	for _, method := range cls.Methods {
+		if kg.isPlaceholder(method.Original) {
+			continue
+		}
+		if method.Name == cls.Name {
+			rpt.constructors++
+			kg.writeConstructor(&body, cls, method, superClass != "")
+			continue
+		}
+
+		if method.IsOverride {
+			rpt.inherited = append(rpt.inherited, method.Name)
+		} else {
+			lowerName := strings.ToLower(method.Name)
+			if strings.HasPrefix(lowerName, "get") {
+				rpt.getters = append(rpt.getters, utils.LowercaseFirst(strings.TrimPrefix(method.Name, "get")))
+			} else if strings.HasPrefix(lowerName, "set") {
+				rpt.setters = append(rpt.setters, utils.LowercaseFirst(strings.TrimPrefix(method.Name, "set")))
+			} else {
+				rpt.methods = append(rpt.methods, method.Name)
+			}
+		}
+
+		if method.IsStatic {
+			kg.writeMethod(&companion, cls, method, "        ")
+		} else {
+			kg.writeMethod(&body, cls, method, "    ")
+		}
+	}
+
+	if companion.Len() > 0 {
+		body.WriteString("    companion object {\n")
+		body.WriteString(strings.TrimRight(companion.String(), "\n") + "\n")
+		body.WriteString("    }\n\n")
+	}
+
+	if isData && body.Len() == 0 {
+		sb.WriteString("\n")
+	} else {
+		sb.WriteString(" {\n\n")
+		sb.WriteString(body.String())
+		sb.WriteString("}\n")
+	}
+
+	return &GeneratedArtifact{
+		FileName:    fileName,
+		Content:     sb.String(),
+		ReportEntry: rpt.render(cls),
+	}, nil
+}
+
+// isDataClass reports whether a class becomes a data class: records, and plain classes that only hold
+// fields (no methods, no subclasses, not an exception).
+// isDataClass cho biết một lớp có trở thành data class hay không: các record, và các lớp thường chỉ chứa
+// trường (không có phương thức, không có lớp con, không phải ngoại lệ).
+func (kg *KotlinGenerator) isDataClass(cls *models.ClassModel) bool {
+	components := 0
+	for _, f := range cls.Fields {
+		if !f.IsStatic && !kg.isPlaceholder(f.Original) {
+			components++
+		}
+	}
+	if components == 0 {
+		// Data class cần ít nhất một tham số trong hàm khởi tạo chính
+		return false
+	}
+	if cls.Type == models.Record {
+		return true
+	}
+	if cls.Type != models.Class || cls.ExceptionBase() != "" || kg.openClasses[cls.Name] {
+		return false
+	}
+	for _, m := range cls.Methods {
+		if !kg.isPlaceholder(m.Original) {
+			return false
+		}
+	}
+	return true
+}
+
+// writeProperty writes a field as a Kotlin property; static fields go to the companion object.
+// Types without an obvious default value are declared lateinit.
+// writeProperty ghi một trường thành thuộc tính Kotlin; các trường tĩnh nằm trong companion object.
+// Các kiểu không có giá trị mặc định hiển nhiên được khai báo lateinit.
+func (kg *KotlinGenerator) writeProperty(body, companion *strings.Builder, field models.Field, public bool) {
+	sb, indent := body, "    "
+	if field.IsStatic {
+		sb, indent = companion, "        "
+	}
+
+	ktType, nullable := kg.kotlinType(field.Type, field.Multiplicity, field.InitialValue)
+	value := kotlinExpression(field.InitialValue)
+	if value == "" {
+		value = kotlinDefault(ktType, nullable)
+	}
+
+	visibility := kotlinVisibility(field.Visibility)
+	if public {
+		visibility = ""
+	}
+	keyword := "var"
+	switch {
+	case field.IsStatic && field.IsFinal && value != "" && kotlinDefaults[ktType] != "" && !nullable:
+		// Hằng số thời gian biên dịch: chỉ kiểu nguyên thủy và String
+		keyword = "const val"
+	case field.IsFinal && value != "":
+		keyword = "val"
+	case value == "":
+		keyword = "lateinit var"
+	}
+
+	kg.writeAnnotations(sb, indent, field.Annotations)
+	sb.WriteString(fmt.Sprintf("%s%s%s %s: %s", indent, visibility, keyword, kotlinName(field.Name), ktType))
+	if value != "" {
+		sb.WriteString(" = " + value)
+	}
+	sb.WriteString("\n")
+}
+
+// writeConstructor writes a secondary constructor that assigns the parameters named like a field and passes
+// the others to the superclass, or leaves a placeholder when some parameter has nowhere to go.
+// writeConstructor ghi một hàm khởi tạo phụ gán các tham số trùng tên với một trường và chuyển các tham số
+// còn lại cho lớp cha, hoặc để lại chỗ giữ khi có tham số không được dùng.
+func (kg *KotlinGenerator) writeConstructor(sb *strings.Builder, cls *models.ClassModel, method models.Method, hasSuper bool) {
+	kg.writeAnnotations(sb, "    ", method.Annotations)
+	kg.writeThrows(sb, "    ", method.Throws)
+	params, names := kg.parameters(method)
+	// Tham số trùng tên với một trường được gán, các tham số còn lại được chuyển cho lớp cha
+	var assigned, rest []string
+	for _, pName := range names {
+		isField := false
+		for _, field := range cls.Fields {
+			if field.Name == pName {
+				isField = true
+				break
+			}
+		}
+		if isField {
+			assigned = append(assigned, kotlinName(pName))
+		} else {
+			rest = append(rest, kotlinName(pName))
+		}
+	}
+	sb.WriteString(fmt.Sprintf("    %sconstructor(%s)", kotlinVisibility(method.Visibility), params))
+	if hasSuper {
+		// Không có hàm khởi tạo chính: hàm khởi tạo phụ phải ủy quyền cho lớp cha
+		sb.WriteString(fmt.Sprintf(" : super(%s)", strings.Join(rest, ", ")))
+		rest = nil
+	}
+	sb.WriteString(" {\n")
+	for _, name := range assigned {
+		sb.WriteString(fmt.Sprintf("        this.%s = %s\n", name, name))
+	}
+	if len(assigned) == 0 || len(rest) > 0 {
+		sb.WriteString("        //Add your code here\n")
+	}
+	sb.WriteString("    }\n\n")
+}
+
+// writeMethod writes a function with its modifiers and a placeholder body.
+// writeMethod ghi một hàm cùng các từ khóa sửa đổi và thân giữ chỗ.
+func (kg *KotlinGenerator) writeMethod(sb *strings.Builder, cls *models.ClassModel, method models.Method, indent string) {
+	kg.writeAnnotations(sb, indent, method.Annotations)
+	kg.writeThrows(sb, indent, method.Throws)
+	if method.IsStatic {
+		sb.WriteString(indent + "@JvmStatic\n")
+	}
+
+	mod := ""
+	hasBody := !method.IsAbstract
+	switch {
+	case cls.Type == models.Interface:
+		// Phương thức default của Java có thân trong giao diện Kotlin
+		hasBody = method.Visibility == "default" || method.IsStatic
+		// Giao diện khai báo lại phương thức của giao diện cha cũng phải ghi override
+		if method.IsOverride || kg.overridesSuper(cls, method) {
+			mod = "override "
+		}
+	case method.IsOverride || kg.overridesSuper(cls, method):
+		mod = kotlinVisibility(method.Visibility) + "override "
+	case method.IsAbstract:
+		mod = kotlinVisibility(method.Visibility) + "abstract "
+	case (cls.Type == models.Abstract || kg.openClasses[cls.Name]) && method.Visibility != "private" && !method.IsStatic:
+		// Lớp con chỉ ghi đè được các phương thức open
+		mod = kotlinVisibility(method.Visibility) + "open "
+	default:
+		mod = kotlinVisibility(method.Visibility)
+	}
+
+	params, names := kg.parameters(method)
+	returnType, nullable := kg.kotlinType(method.ReturnType, "", "")
+	signature := fmt.Sprintf("%s%sfun %s(%s)", indent, mod, kotlinName(method.Name), params)
+	if returnType != "Unit" && returnType != "" {
+		signature += ": " + returnType
+	}
+	sb.WriteString(signature)
+	if !hasBody {
+		sb.WriteString("\n\n")
+		return
+	}
+	sb.WriteString(" {\n")
+
+	// Auto-Body
+	// Tự động tạo thân hàm
+	// Tên thuộc tính của getter/setter; rỗng với get(i) hoặc set(v), khi đó thân giữ chỗ được dùng
+	lowerName := strings.ToLower(method.Name)
+	property := utils.LowercaseFirst(method.Name[min(3, len(method.Name)):])
+	switch {
+	case strings.HasPrefix(lowerName, "get") && property != "" && returnType != "Unit":
+		sb.WriteString(fmt.Sprintf("%s    return %s\n", indent, kotlinName(property)))
+	case strings.HasPrefix(lowerName, "set") && property != "" && len(names) > 0:
+		sb.WriteString(fmt.Sprintf("%s    this.%s = %s\n", indent, kotlinName(property), kotlinName(names[0])))
+	case returnType != "Unit" && returnType != "":
+		sb.WriteString(indent + "    //Add your code here\n")
+		if value := kotlinDefault(returnType, nullable); value != "" {
+			sb.WriteString(fmt.Sprintf("%s    return %s\n", indent, value))
+		} else {
+			sb.WriteString(indent + "    TODO(\"Not yet implemented\")\n")
+		}
+	default:
+		sb.WriteString(indent + "    //Add your code here\n")
+	}
+	sb.WriteString(indent + "}\n\n")
+}
+
+// parameters renders the parameter list of a method ("id: Long, vararg tags: String") and returns the names used.
+// parameters hiển thị danh sách tham số của phương thức ("id: Long, vararg tags: String") và trả về các tên đã dùng.
+func (kg *KotlinGenerator) parameters(method models.Method) (string, []string) {
+	var params, names []string
+	for i, p := range method.Parameters {
+		pName := p.Name
+		if pName == "" {
+			// Biểu đồ chỉ ghi kiểu (add(int, int)): đặt tên theo vị trí
+			pName = fmt.Sprintf("arg%d", i)
+		}
+		prefix := ""
+		pType := strings.TrimSpace(p.Type)
+		if strings.HasSuffix(pType, "...") {
+			prefix, pType = "vararg ", strings.TrimSuffix(pType, "...")
+		}
+		ktType, _ := kg.kotlinType(pType, "", "")
+		param := fmt.Sprintf("%s%s: %s", prefix, kotlinName(pName), ktType)
+		if p.Default != "" {
+			param += " = " + kotlinExpression(p.Default)
+		}
+		params = append(params, param)
+		names = append(names, pName)
+	}
+	return strings.Join(params, ", "), names
+}
+
+// typeParameters renders the generic parameters of a class (<T, ID : Serializable>) and, when a parameter
+// has several bounds, the matching where clause.
+// typeParameters hiển thị các tham số kiểu của lớp (<T, ID : Serializable>) và, khi một tham số có nhiều
+// cận trên, mệnh đề where tương ứng.
+func (kg *KotlinGenerator) typeParameters(cls *models.ClassModel) (string, string) {
+	if len(cls.TypeParameters) == 0 {
+		return "", ""
+	}
+	var params, where []string
+	for _, tp := range cls.TypeParameters {
+		switch len(tp.Bounds) {
+		case 0:
+			params = append(params, tp.Name)
+		case 1:
+			bound, _ := kg.kotlinType(tp.Bounds[0], "", "")
+			params = append(params, tp.Name+" : "+bound)
+		default:
+			params = append(params, tp.Name)
+			for _, b := range tp.Bounds {
+				bound, _ := kg.kotlinType(b, "", "")
+				where = append(where, tp.Name+" : "+bound)
+			}
+		}
+	}
+	whereClause := ""
+	if len(where) > 0 {
+		whereClause = " where " + strings.Join(where, ", ")
+	}
+	return "<" + strings.Join(params, ", ") + ">", whereClause
+}
+
+// kotlinType converts a Java type from the diagram to Kotlin and reports whether it is nullable:
+// a "?" suffix, a 0..1 multiplicity (on the association or written as "String [0..1]") or a null
+// initial value make it nullable.
+// kotlinType chuyển một kiểu Java từ biểu đồ sang Kotlin và cho biết nó có thể null hay không:
+// hậu tố "?", bội số 0..1 (trên liên kết hoặc được viết như "String [0..1]") hoặc giá trị khởi tạo
+// null làm nó có thể null.
+func (kg *KotlinGenerator) kotlinType(javaType, multiplicity, initialValue string) (string, bool) {
+	t := strings.TrimSpace(javaType)
+	if match := reTypeMultiplicity.FindStringSubmatch(t); match != nil {
+		multiplicity = match[1]
+		t = strings.TrimSpace(t[:len(t)-len(match[0])])
+	}
+	nullable := strings.ReplaceAll(multiplicity, " ", "") == "0..1" || strings.TrimSpace(initialValue) == "null"
+	if strings.HasSuffix(t, "?") {
+		nullable = true
+		t = strings.TrimSpace(strings.TrimSuffix(t, "?"))
+	}
+	if t == "" {
+		return "", nullable
+	}
+
+	ref, err := models.ParseType(t)
+	if err != nil {
+		// Kiểu không phân tích được được giữ nguyên
+		converted := t
+		if nullable {
+			converted += "?"
+		}
+		return converted, nullable
+	}
+	converted := kotlinTypeRef(ref)
+	if nullable && converted != "Unit" {
+		converted += "?"
+	}
+	return converted, nullable
+}
+
+// kotlinTypeRef renders a parsed Java type in Kotlin syntax: generics keep their arguments, wildcards
+// become out/in projections and arrays become IntArray or Array<T>.
+// kotlinTypeRef hiển thị một kiểu Java đã phân tích theo cú pháp Kotlin: kiểu tổng quát giữ đối số,
+// ký tự đại diện trở thành phép chiếu out/in và mảng trở thành IntArray hoặc Array<T>.
+func kotlinTypeRef(ref models.TypeRef) string {
+	if ref.Name == "?" {
+		switch {
+		case ref.Bound == nil:
+			return "*"
+		case ref.BoundKind == "super":
+			return "in " + kotlinTypeRef(*ref.Bound)
+		}
+		return "out " + kotlinTypeRef(*ref.Bound)
+	}
+
+	name := ref.Name
+	if mapped, ok := kotlinTypes[name]; ok {
+		name = mapped
+	}
+	if len(ref.Args) > 0 {
+		var args []string
+		for _, a := range ref.Args {
+			args = append(args, kotlinTypeRef(a))
+		}
+		name += "<" + strings.Join(args, ", ") + ">"
+	}
+
+	for i := 0; i < ref.Dims; i++ {
+		if array, ok := kotlinArrays[ref.Name]; ok && i == 0 {
+			name = array
+		} else {
+			name = "Array<" + name + ">"
+		}
+	}
+	if ref.Varargs {
+		// Tham số vararg dùng kiểu phần tử; ở nơi khác T... là một mảng
+		if array, ok := kotlinArrays[ref.Name]; ok && ref.Dims == 0 {
+			return array
+		}
+		return "Array<" + name + ">"
+	}
+	return name
+}
+
+// kotlinDefault returns the initial value of a property of the given type, or "" when there is none.
+// kotlinDefault trả về giá trị khởi tạo của một thuộc tính có kiểu đã cho, hoặc "" khi không có.
+func kotlinDefault(ktType string, nullable bool) string {
+	if nullable {
+		return "null"
+	}
+	if value, ok := kotlinDefaults[ktType]; ok {
+		return value
+	}
+	switch base := ktType[:strings.IndexAny(ktType+"<", "<")]; base {
+	case "List", "MutableList", "Collection":
+		return "mutableListOf()"
+	case "Set", "MutableSet":
+		return "mutableSetOf()"
+	case "Map", "MutableMap":
+		return "mutableMapOf()"
+	case "ArrayList", "LinkedList", "HashSet", "LinkedHashSet", "TreeSet", "HashMap", "LinkedHashMap", "TreeMap":
+		// Lớp cụ thể: gọi hàm khởi tạo, đối số kiểu được suy ra từ khai báo
+		return base + "()"
+	}
+	return ""
+}
+
+// kotlinExpression converts a Java initial value to Kotlin (new Foo() -> Foo()).
+// kotlinExpression chuyển một giá trị khởi tạo Java sang Kotlin (new Foo() -> Foo()).
+func kotlinExpression(value string) string {
+	value = strings.TrimSpace(value)
+	return strings.TrimSpace(strings.TrimPrefix(value, "new "))
+}
+
+// kotlinVisibility returns the Kotlin modifier for a Java visibility, with a trailing space ("" for public).
+// kotlinVisibility trả về từ khóa Kotlin cho một phạm vi truy cập Java, kèm khoảng trắng ("" với public).
+func kotlinVisibility(visibility string) string {
+	switch visibility {
+	case "private", "protected":
+		return visibility + " "
+	case "package":
+		return "internal "
+	}
+	return ""
+}
+
+// kotlinName escapes names that are Kotlin keywords with backticks (object -> `object`).
+// kotlinName bọc các tên là từ khóa Kotlin bằng dấu backtick (object -> `object`).
+func kotlinName(name string) string {
+	if kotlinKeywords[name] {
+		return "`" + name + "`"
+	}
+	return name
+}
+
+// isPlaceholder reports whether a member is the "getters/setters" placeholder.
+// isPlaceholder cho biết một thành viên có phải là phần giữ chỗ "getters/setters" hay không.
+func (kg *KotlinGenerator) isPlaceholder(original string) bool {
+	return strings.Contains(strings.ToLower(original), "getters/setters")
+}
+
+// hasPlaceholder reports whether the class asks for getters and setters.
+// hasPlaceholder cho biết lớp có yêu cầu getter và setter hay không.
+func (kg *KotlinGenerator) hasPlaceholder(cls *models.ClassModel) bool {
+	for _, f := range cls.Fields {
+		if kg.isPlaceholder(f.Original) {
+			return true
+		}
+	}
+	for _, m := range cls.Methods {
+		if kg.isPlaceholder(m.Original) {
+			return true
+		}
+	}
+	return false
+}
+
+// overridesSuper reports whether a method declared in the diagram overrides a method of a supertype
+// (same name and parameters, a type parameter of the supertype matching any type). Kotlin requires override there.
+// overridesSuper cho biết một phương thức khai báo trong biểu đồ có ghi đè phương thức của kiểu cha hay không
+// (cùng tên và tham số, tham số kiểu của kiểu cha khớp mọi kiểu). Kotlin bắt buộc override trong trường hợp này.
+func (kg *KotlinGenerator) overridesSuper(cls *models.ClassModel, method models.Method) bool {
+	if method.IsStatic || method.Visibility == "private" {
+		return false
+	}
+	visited := map[string]bool{cls.Name: true}
+	var walk func(c *models.ClassModel) bool
+	walk = func(c *models.ClassModel) bool {
+		for _, rel := range c.Relationships {
+			if rel.Kind != models.Generalization && rel.Kind != models.Realization {
+				continue
+			}
+			super, ok := kg.classes[rel.Target]
+			if !ok || visited[super.Name] {
+				continue
+			}
+			visited[super.Name] = true
+			for _, m := range super.Methods {
+				if !m.IsStatic && m.Visibility != "private" && sameParameters(super, m, method) {
+					return true
+				}
+			}
+			if walk(super) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(cls)
+}
+
+// sameParameters reports whether sub has the name and parameter types of the method m declared in super.
+// sameParameters cho biết sub có cùng tên và kiểu tham số với phương thức m được khai báo trong super hay không.
+func sameParameters(super *models.ClassModel, m, sub models.Method) bool {
+	if m.Name != sub.Name || len(m.Parameters) != len(sub.Parameters) {
+		return false
+	}
+	for i, p := range m.Parameters {
+		pType := strings.ReplaceAll(p.Type, " ", "")
+		if pType == strings.ReplaceAll(sub.Parameters[i].Type, " ", "") {
+			continue
+		}
+		isTypeParam := false
+		for _, tp := range super.TypeParameters {
+			isTypeParam = isTypeParam || tp.Name == pType
+		}
+		if !isTypeParam {
+			return false
+		}
+	}
+	return true
+}
+
+// hasConstructor reports whether the diagram declares a constructor for the class.
+// hasConstructor cho biết biểu đồ có khai báo hàm khởi tạo cho lớp hay không.
+func (kg *KotlinGenerator) hasConstructor(cls *models.ClassModel) bool {
+	for _, m := range cls.Methods {
+		if m.Name == cls.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// writeExceptionConstructors writes the four standard constructors of an exception class and returns their count.
+// writeExceptionConstructors ghi bốn hàm khởi tạo tiêu chuẩn của một lớp ngoại lệ và trả về số lượng của chúng.
+func (kg *KotlinGenerator) writeExceptionConstructors(sb *strings.Builder) int {
+	constructors := []struct{ params, args string }{
+		{"", ""},
+		{"message: String?", "message"},
+		{"message: String?, cause: Throwable?", "message, cause"},
+		{"cause: Throwable?", "cause"},
+	}
+	for _, c := range constructors {
+		sb.WriteString(fmt.Sprintf("    constructor(%s) : super(%s)\n\n", c.params, c.args))
+	}
+	return len(constructors)
+}
+
+// writeThrows writes @Throws for the declared exceptions, since Kotlin has no throws clause.
+// writeThrows ghi @Throws cho các ngoại lệ được khai báo, vì Kotlin không có mệnh đề throws.
+func (kg *KotlinGenerator) writeThrows(sb *strings.Builder, indent string, throws []string) {
+	if len(throws) == 0 {
+		return
+	}
+	var classes []string
+	for _, t := range throws {
+		classes = append(classes, t+"::class")
+	}
+	sb.WriteString(indent + "@Throws(" + strings.Join(classes, ", ") + ")\n")
+}
+
+// writeAnnotations writes one annotation per line with the given indentation.
+// writeAnnotations ghi mỗi chú thích trên một dòng với thụt lề cho trước.
+func (kg *KotlinGenerator) writeAnnotations(sb *strings.Builder, indent string, annotations []string) {
+	for _, a := range annotations {
+		sb.WriteString(indent + a + "\n")
+	}
+}
+
+// checkImports identifies necessary imports for the class. Kotlin collections need none.
+// checkImports xác định các mục nhập khẩu cần thiết cho lớp. Các collection của Kotlin không cần import.
+func (kg *KotlinGenerator) checkImports(cls *models.ClassModel) []string {
+	imports := make(map[string]bool)
+
+	// Check types
+	// Kiểm tra các kiểu
+	checkType := func(t string) {
+		for _, name := range reTypeName.FindAllString(t, -1) {
+			switch name {
+			case "Optional":
+				imports["java.util.Optional"] = true
+			case "Serializable":
+				imports["java.io.Serializable"] = true
+			case "LocalDate", "LocalTime", "LocalDateTime", "Instant", "Duration":
+				imports["java.time."+name] = true
+			case "Date":
+				imports["java.util.Date"] = true
+			}
+		}
+	}
+
+	// Cross-package references to classes of the diagram
+	// Tham chiếu giữa các gói tới các lớp của biểu đồ
+	ownPackage := kg.packageOf(cls)
+	checkClassRefs := func(t string) {
+		checkType(t)
+		for _, ref := range reTypeName.FindAllString(t, -1) {
+			refPackage, ok := kg.classPackages[ref]
+			if ok && ref != cls.Name && refPackage != "" && refPackage != ownPackage {
+				imports[refPackage+"."+ref] = true
+			}
+		}
+	}
+
+	for _, f := range cls.Fields {
+		checkClassRefs(f.Type)
+	}
+	for _, m := range cls.Methods {
+		checkClassRefs(m.ReturnType)
+		for _, p := range m.Parameters {
+			checkClassRefs(p.Type)
+		}
+		for _, t := range m.Throws {
+			checkClassRefs(t)
+			if pkg, ok := exceptionPackages[t]; ok {
+				imports[pkg+"."+t] = true
+			}
+		}
+	}
+	for _, rel := range cls.Relationships {
+		if rel.Kind == models.Generalization || rel.Kind == models.Realization {
+			checkClassRefs(rel.TargetType())
+		}
+	}
+	for _, tp := range cls.TypeParameters {
+		for _, b := range tp.Bounds {
+			checkClassRefs(b)
+		}
+	}
+
+	var keys []string
+	for k := range imports {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,147 @@
+package generator
+
+import (
+	"nUML/analyzer"
+	"strings"
+	"testing"
+)
+
+func TestKotlinType(t *testing.T) {
+	tests := []struct {
+		javaType, multiplicity, initialValue string
+		want                                 string
+		nullable                             bool
+	}{
+		{javaType: "int", want: "Int"},
+		{javaType: "Integer", want: "Int"},
+		{javaType: "boolean", want: "Boolean"},
+		{javaType: "String", multiplicity: "0..1", want: "String?", nullable: true},
+		{javaType: "String [0..1]", want: "String?", nullable: true},
+		{javaType: "String?", want: "String?", nullable: true},
+		{javaType: "Object", initialValue: "null", want: "Any?", nullable: true},
+		{javaType: "List<Integer>", want: "List<Int>"},
+		{javaType: "HashMap<String, Integer>", want: "HashMap<String, Int>"},
+		{javaType: "int[]", want: "IntArray"},
+		{javaType: "String[]", want: "Array<String>"},
+		{javaType: "String...", want: "Array<String>"},
+	}
+
+	kg := NewKotlinGenerator("")
+	for _, tt := range tests {
+		t.Run(tt.javaType+" "+tt.multiplicity+" "+tt.initialValue, func(t *testing.T) {
+			got, nullable := kg.kotlinType(tt.javaType, tt.multiplicity, tt.initialValue)
+			if got != tt.want || nullable != tt.nullable {
+				t.Errorf("kotlinType() = %s, %v; want %s, %v", got, nullable, tt.want, tt.nullable)
+			}
+		})
+	}
+}
+
+func TestKotlinGeneratorGenerate(t *testing.T) {
+	source := `
+abstract class Shape {
+  - name : String
+  + Shape(name : String)
+  + {abstract} area() : double
+  + {static} count() : int
+}
+class Circle extends Shape {
+  - radius : double
+  + Circle(name : String, radius : double)
+}
+interface Drawable {
+  + draw() : void
+}
+class Square extends Shape implements Drawable {
+}
+record Point(int x, int y)
+class Address {
+  - street : String
+  - zip : String [0..1]
+}
+enum Color {
+  RED, GREEN
+}
+class NotFound <<exception>>
+interface Solid extends Drawable {
+  + draw() : void
+}
+class Box<T> {
+  + get(i : int) : T
+  + set(value : T) : void
+}
+`
+	classes, err := analyzer.NewAnalyzerService(analyzer.OrderName).AnalyzePlantUML([]string{source})
+	if err != nil {
+		t.Fatalf("AnalyzePlantUML failed: %v", err)
+	}
+	kg := NewKotlinGenerator("")
+	kg.RegisterClasses(classes)
+	generated := make(map[string]string)
+	for _, cls := range classes {
+		artifact, err := kg.Generate(cls)
+		if err != nil {
+			t.Fatalf("Generate(%s) failed: %v", cls.Name, err)
+		}
+		generated[artifact.FileName] = artifact.Content
+	}
+
+	tests := []struct {
+		file string
+		want []string // Các đoạn phải có trong tệp
+	}{
+		{file: "Shape.kt", want: []string{
+			"abstract class Shape {",
+			"    constructor(name: String) {\n        this.name = name\n    }",
+			"    abstract fun area(): Double\n",
+			"    companion object {\n        @JvmStatic\n        fun count(): Int {",
+		}},
+		{file: "Circle.kt", want: []string{
+			"class Circle : Shape {",
+			"    constructor(name: String, radius: Double) : super(name) {\n        this.radius = radius\n    }",
+			"    override fun area(): Double {",
+		}},
+		{file: "Square.kt", want: []string{"class Square : Shape(), Drawable {", "    override fun draw() {"}},
+		{file: "Drawable.kt", want: []string{"interface Drawable {\n\n    fun draw()\n"}},
+		{file: "Point.kt", want: []string{"data class Point(\n    val x: Int,\n    val y: Int\n)\n"}},
+		{file: "Address.kt", want: []string{"data class Address(\n    var street: String,\n    var zip: String? = null\n)\n"}},
+		{file: "Color.kt", want: []string{"enum class Color {\n\n    RED, GREEN\n\n}"}},
+		{file: "Solid.kt", want: []string{"    override fun draw()\n"}},
+		{file: "Box.kt", want: []string{
+			"    fun get(i: Int): T {\n        //Add your code here\n        TODO(\"Not yet implemented\")\n    }",
+			"    fun set(value: T) {\n        //Add your code here\n    }",
+		}},
+		{file: "NotFound.kt", want: []string{"class NotFound : Exception {", "    constructor(message: String?, cause: Throwable?) : super(message, cause)\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			content, ok := generated[tt.file]
+			if !ok {
+				t.Fatalf("%s was not generated", tt.file)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(content, w) {
+					t.Errorf("%s does not contain %q:\n%s", tt.file, w, content)
+				}
+			}
+		})
+	}
+}
+
+func TestKotlinConstructorWithoutSuper(t *testing.T) {
+	classes, err := analyzer.NewAnalyzerService(analyzer.OrderName).AnalyzePlantUML([]string{"class Timer {\n  - delay : long\n  + Timer(seconds : int)\n}\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kg := NewKotlinGenerator("")
+	kg.RegisterClasses(classes)
+	artifact, err := kg.Generate(classes[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Tham số không trùng tên trường và không có lớp cha: thân giữ chỗ thay vì thân rỗng
+	want := "    constructor(seconds: Int) {\n        //Add your code here\n    }\n"
+	if !strings.Contains(artifact.Content, want) {
+		t.Errorf("Timer.kt does not contain %q:\n%s", want, artifact.Content)
+	}
+}
@@ -0,0 +1,80 @@
+package generator
+
+import (
+	"fmt"
+	"nUML/models"
+	"strings"
+)
+
+// classReport collects what a generator created for one class, for its entry in Report.md.
+// classReport thu thập những gì một bộ sinh mã đã tạo cho một lớp, cho mục của nó trong Report.md.
+type classReport struct {
+	attributes   []string // Fields and enum constants // Các trường và hằng số enum
+	constructors int      // Number of constructors // Số hàm khởi tạo
+	getters      []string // Fields with a getter // Các trường có getter
+	setters      []string // Fields with a setter // Các trường có setter
+	inherited    []string // Overridden methods // Các phương thức được ghi đè
+	methods      []string // Other methods // Các phương thức khác
+}
+
+// render returns the markdown entry of cls, the same for every target language.
+// render trả về mục markdown của cls, giống nhau cho mọi ngôn ngữ đích.
+func (r *classReport) render(cls *models.ClassModel) string {
+	var rpt strings.Builder
+	rpt.WriteString(fmt.Sprintf("# %s [.]\n", cls.Name))
+
+	// Attributes
+	// Thuộc tính
+	if len(r.attributes) > 0 {
+		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo các thuộc tính (Created attributes): {%s}\n", strings.Join(r.attributes, ", ")))
+	} else {
+		rpt.WriteString("- [.] Đã tạo các thuộc tính (Created attributes): {}\n")
+	}
+
+	// Constructors
+	// Hàm khởi tạo
+	if r.constructors > 0 {
+		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo {%d} constructor(s)\n", r.constructors))
+	}
+
+	// Getters
+	if len(r.getters) > 0 {
+		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo getter cho (Created getters for): { %s }\n", strings.Join(r.getters, ", ")))
+	}
+
+	// Setters
+	if len(r.setters) > 0 {
+		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo setter cho (Created setters for): { %s }\n", strings.Join(r.setters, ", ")))
+	}
+
+	// Inherited
+	// Kế thừa
+	if len(r.inherited) > 0 {
+		rpt.WriteString(fmt.Sprintf("- [.] Đã thừa kế (override) các phương thức (Overridden methods): { %s }\n", strings.Join(r.inherited, ", ")))
+	}
+
+	// Relationships
+	// Các mối quan hệ
+	if len(cls.Relationships) > 0 {
+		var rels []string
+		for _, rel := range cls.Relationships {
+			rels = append(rels, fmt.Sprintf("%s %s", rel.Kind, rel.Target))
+		}
+		rpt.WriteString(fmt.Sprintf("- [.] Quan hệ (Relationships): { %s }\n", strings.Join(rels, ", ")))
+	}
+
+	// Custom Methods
+	// Phương thức tùy chỉnh
+	for _, cm := range r.methods {
+		rpt.WriteString(fmt.Sprintf("- [.] Đã tạo phương thức (Created method): %s\n", cm))
+	}
+
+	// Warnings from the analysis (e.g. return type clashes)
+	// Cảnh báo từ bước phân tích (ví dụ xung đột kiểu trả về)
+	for _, entry := range cls.LogEntries {
+		rpt.WriteString(fmt.Sprintf("- [!] %s\n", entry))
+	}
+
+	rpt.WriteString("\n")
+	return rpt.String()
+}
@@ -4,26 +4,53 @@ import (
 	"fmt"
 	"io/ioutil"
 	"nUML/analyzer"
+	"nUML/exporter"
 	"nUML/generator"
 	"nUML/models"
+	"nUML/output"
 	"nUML/utils"
 	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
 )
 
 var targetPackage string
 var OverwriteMode bool
 var NoReportMode bool
+var PageNames []string
+var ValidateMode bool
+var OutputFormat = "text"
+var FixMode bool
+var MergeMode bool
+var CheckMode bool
+var DryRunMode bool
+var OutputDest string
+var Jobs = runtime.NumCPU()
+var ClassOrder = analyzer.OrderName
+var Language = "java"
 
 func printHelp() {
 	fmt.Println("nUML: The Java Class Diagram Generator")
 	fmt.Println("Author: Thai Thanh Nguyen")
-	fmt.Println("Usage: nUML [options] <file.drawio>")
+	fmt.Println("Usage: nUML [options] <file.drawio | file.mmd | file.md | file.puml>")
+	fmt.Println("       nUML validate [options] <file>   Check the diagram and print diagnostics, exit code 1 on errors (Kiểm tra biểu đồ và in các chẩn đoán, mã thoát 1 khi có lỗi).")
 	fmt.Println("Options:")
 	fmt.Println("  -f <folder>   Generate files in the specified folder and add package declaration (Tạo tệp trong thư mục và thêm khai báo gói).")
 	fmt.Println("  -o            Overwrite existing files (default: false) (Ghi đè tệp hiện có (mặc định: sai)).")
+	fmt.Println("  -m            Merge into existing files, keeping hand-written code (Hợp nhất vào tệp hiện có, giữ lại mã viết tay).")
 	fmt.Println("  -v            Verbose mode (print detailed progress) (Chế độ chi tiết (in tiến trình chi tiết)).")
 	fmt.Println("  -l            Skip generation of Report.md (Bỏ qua việc tạo Report.md).")
+	fmt.Println("  -p <pages>    Only process the named pages, comma separated (default: all pages) (Chỉ xử lý các trang được đặt tên, phân cách bằng dấu phẩy (mặc định: tất cả các trang)).")
+	fmt.Println("  --format <f>  Diagnostics format for validate: text, json or sarif (default: text) (Định dạng chẩn đoán cho validate: text, json hoặc sarif (mặc định: text)).")
+	fmt.Println("  --fix         Rewrite the .drawio file so it matches what was understood (Ghi lại tệp .drawio cho khớp với những gì đã hiểu).")
+	fmt.Println("  --check       Compare generated code with the files on disk without writing, exit code 1 on differences (So sánh mã được tạo với tệp trên đĩa mà không ghi, mã thoát 1 khi khác nhau).")
+	fmt.Println("  --output <d>  Write the files to a folder (default), \"-\" for stdout, or a .zip, .tar or .tar.gz archive (Ghi tệp vào thư mục (mặc định), \"-\" cho stdout, hoặc tệp nén .zip, .tar hoặc .tar.gz).")
+	fmt.Println("  --dry-run     List the files that would be created, overwritten, merged or skipped without writing (Liệt kê các tệp sẽ được tạo, ghi đè, hợp nhất hoặc bỏ qua mà không ghi).")
+	fmt.Println("  -j <n>        Number of files generated and written in parallel (default: number of CPUs) (Số tệp được tạo và ghi song song (mặc định: số CPU)).")
+	fmt.Println("  --order <o>   Order of the classes in generation and Report.md: name, position or topological (default: name) (Thứ tự các lớp khi tạo và trong Report.md: name, position hoặc topological (mặc định: name)).")
+	fmt.Println("  --lang <l>    Language of the generated code: java or kotlin (default: java) (Ngôn ngữ của mã được tạo: java hoặc kotlin (mặc định: java)).")
 	fmt.Println("  -h            Show this help message (Hiển thị tin nhắn trợ giúp này).")
 }
 
@@ -42,6 +69,12 @@ func main() {
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 		switch arg {
+		case "validate":
+			if i == 0 {
+				ValidateMode = true
+			} else {
+				inputFile = arg
+			}
 		case "-h":
 			printHelp()
 			return
@@ -53,93 +86,417 @@ func main() {
 				fmt.Println("Error: -f requires a folder name (Lỗi: -f yêu cầu tên thư mục)")
 				return
 			}
+		case "--format":
+			if i+1 < len(args) {
+				OutputFormat = strings.ToLower(args[i+1])
+				i++
+			} else {
+				fmt.Println("Error: --format requires text, json or sarif (Lỗi: --format yêu cầu text, json hoặc sarif)")
+				return
+			}
+		case "--fix":
+			FixMode = true
+		case "--check":
+			CheckMode = true
+		case "-j":
+			if i+1 < len(args) {
+				n, err := strconv.Atoi(args[i+1])
+				if err != nil || n < 1 {
+					fmt.Println("Error: -j requires a positive number (Lỗi: -j yêu cầu một số dương)")
+					return
+				}
+				Jobs = n
+				i++
+			} else {
+				fmt.Println("Error: -j requires a positive number (Lỗi: -j yêu cầu một số dương)")
+				return
+			}
+		case "--order":
+			if i+1 < len(args) {
+				order, err := analyzer.ParseClassOrder(args[i+1])
+				if err != nil {
+					fmt.Printf("Error (Lỗi): %v\n", err)
+					os.Exit(1)
+				}
+				ClassOrder = order
+				i++
+			} else {
+				fmt.Println("Error: --order requires name, position or topological (Lỗi: --order yêu cầu name, position hoặc topological)")
+				return
+			}
+		case "--lang":
+			if i+1 < len(args) {
+				Language = strings.ToLower(args[i+1])
+				i++
+			} else {
+				fmt.Println("Error: --lang requires java or kotlin (Lỗi: --lang yêu cầu java hoặc kotlin)")
+				return
+			}
+		case "--dry-run":
+			DryRunMode = true
+		case "--output":
+			if i+1 < len(args) {
+				OutputDest = args[i+1]
+				i++
+			} else {
+				fmt.Println("Error: --output requires a folder, \"-\" or an archive name (Lỗi: --output yêu cầu thư mục, \"-\" hoặc tên tệp nén)")
+				return
+			}
 		case "-o":
 			OverwriteMode = true
+		case "-m":
+			MergeMode = true
 		case "-v":
 			utils.VerboseMode = true
 		case "-l":
 			NoReportMode = true
+		case "-p":
+			if i+1 < len(args) {
+				PageNames = append(PageNames, strings.Split(args[i+1], ",")...)
+				i++
+			} else {
+				fmt.Println("Error: -p requires a page name (Lỗi: -p yêu cầu tên trang)")
+				return
+			}
 		default:
 			inputFile = arg
 		}
 	}
 
+	if OutputFormat != "text" && OutputFormat != "json" && OutputFormat != "sarif" {
+		fmt.Printf("Error: unknown format %q, use text, json or sarif (Lỗi: định dạng không xác định)\n", OutputFormat)
+		os.Exit(1)
+	}
+	if Language != "java" && Language != "kotlin" {
+		fmt.Printf("Error: unknown language %q, use java or kotlin (Lỗi: ngôn ngữ không xác định)\n", Language)
+		os.Exit(1)
+	}
+	if MergeMode && Language != "java" {
+		// Bộ hợp nhất chỉ hiểu mã nguồn Java
+		fmt.Println("Error: -m only supports Java (Lỗi: -m chỉ hỗ trợ Java)")
+		os.Exit(1)
+	}
+	// stdout chỉ chứa tài liệu JSON/SARIF, các diff của --check hoặc các tệp của --output -
+	utils.StderrMode = OutputFormat != "text" || CheckMode || OutputDest == "-" && !DryRunMode
+
 	if inputFile == "" {
 		fmt.Println("Error: No input file specified (Lỗi: Không có tệp đầu vào nào được chỉ định)")
+		if ValidateMode {
+			os.Exit(1)
+		}
 		return
 	}
 
 	utils.LogInfo(fmt.Sprintf("Processing file (Đang xử lý tệp): %s", inputFile))
-	// nhận hoặc tạo thư mục đích nếu -f được cung cấp
+	// 1-2. Parsing & Analysis
+	// 1-2. Phân tích cú pháp & Phân tích
+	ana := analyzer.NewAnalyzerService(ClassOrder)
+	classes, err := analyzeFile(ana, inputFile)
+	if err != nil {
+		utils.LogInfo(fmt.Sprintf("Error (Lỗi): %v", err))
+		if ValidateMode {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if FixMode {
+		if err := fixDiagram(inputFile, ana.Diagnostics().Fixes()); err != nil {
+			utils.LogInfo(fmt.Sprintf("Error (Lỗi): %v", err))
+		}
+	}
+
+	if ValidateMode {
+		os.Exit(validate(inputFile, ana.Diagnostics()))
+	}
+
 	if targetPackage != "" {
 		utils.LogVerbose(fmt.Sprintf("Target Package/Folder (Gói/Thư mục đích): %s", targetPackage))
-		if _, err := os.Stat(targetPackage); os.IsNotExist(err) {
-			os.Mkdir(targetPackage, 0755)
-		}
 	}
 
-	// 1. Parsing
-	// 1. Phân tích cú pháp
-	cells, err := models.ParseXML(inputFile)
+	// 3. Generation
+	// 3. Tạo code
+	var gen generator.CodeGenerator
+	switch Language {
+	case "kotlin":
+		kotlinGen := generator.NewKotlinGenerator(targetPackage)
+		kotlinGen.RegisterClasses(classes)
+		gen = kotlinGen
+	default:
+		javaGen := generator.NewJavaGenerator(targetPackage)
+		javaGen.RegisterClasses(classes)
+		gen = javaGen
+	}
+	merger := generator.NewJavaMerger()
+
+	if CheckMode {
+		os.Exit(check(gen, merger, classes, OutputDest))
+	}
+
+	sink, err := openSink(OutputDest)
 	if err != nil {
 		utils.LogInfo(fmt.Sprintf("Error (Lỗi): %v", err))
 		return
 	}
+	writer := generator.NewArtifactWriter(gen, generator.WriteOptions{
+		Overwrite: OverwriteMode,
+		Merge:     MergeMode,
+		DryRun:    DryRunMode,
+		Workers:   Jobs,
+	})
+	results := writer.WriteAll(classes, sink)
 
-	// 2. Analysis
-	// 2. Phân tích
-	ana := analyzer.NewAnalyzerService()
-	classes := ana.AnalyzeDiagram(cells)
+	// Save Report
+	// Lưu báo cáo
+	if !NoReportMode {
+		results = append(results, writer.WriteReport(results, sink))
+	}
+	closeErr := sink.Close()
 
-	// 3. Generation
-	// 3. Tạo code
-	gen := generator.NewJavaGenerator(targetPackage)
+	// 4. Finalize
+	// 4. Hoàn tất
+	failed := printSummary(results)
+	if closeErr != nil {
+		utils.LogInfo(fmt.Sprintf("Error (Lỗi): %v", closeErr))
+		failed++
+	}
+	if failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// openSink returns the destination selected with --output: the filesystem by default, stdout for "-",
+// or an archive chosen by extension. Any other value is a folder the generated paths are written under.
+// openSink trả về đích được chọn bằng --output: hệ thống tệp theo mặc định, stdout với "-", hoặc một
+// tệp nén được chọn theo phần mở rộng. Mọi giá trị khác là thư mục chứa các đường dẫn được tạo.
+func openSink(dest string) (output.Sink, error) {
+	lower := strings.ToLower(dest)
+	switch {
+	case dest == "":
+		return output.NewFileSink(""), nil
+	case dest == "-":
+		return output.NewStdoutSink(os.Stdout), nil
+	case DryRunMode && isArchive(dest):
+		// Chạy thử không tạo tệp nén; mọi tệp đều là tạo mới
+		return output.NewMemorySink(), nil
+	case strings.HasSuffix(lower, ".zip"):
+		return output.NewZipSink(dest)
+	case strings.HasSuffix(lower, ".tar"):
+		return output.NewTarSink(dest, false)
+	case strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz"):
+		return output.NewTarSink(dest, true)
+	}
+	return output.NewFileSink(dest), nil
+}
+
+// printSummary lists every file in class order with what happened to it (what would happen in dry-run
+// mode), followed by the totals, and returns the number of failures.
+// printSummary liệt kê mọi tệp theo thứ tự các lớp cùng điều đã xảy ra với nó (điều sẽ xảy ra ở chế độ
+// chạy thử), theo sau là các tổng số, và trả về số lần thất bại.
+func printSummary(results []generator.FileResult) int {
+	pastTense := map[string]string{"create": "created", "overwrite": "overwritten", "merge": "merged", "skip": "skipped"}
+	counts := make(map[string]int)
+	for _, r := range results {
+		name := r.Path
+		if name == "" {
+			name = r.ClassName
+		}
+		label := r.Action
+		if !DryRunMode {
+			label = pastTense[r.Action]
+		}
+		switch {
+		case r.Err != nil:
+			counts["failed"]++
+			utils.LogInfo(utils.Red + fmt.Sprintf("%-11s %s: %v", "failed", name, r.Err) + utils.Reset)
+		case r.Action == "skip":
+			counts["skip"]++
+			utils.LogInfo(utils.Yellow + fmt.Sprintf("%-11s %s (exists, use -o to overwrite or -m to merge)", label, name) + utils.Reset)
+		default:
+			counts[r.Action]++
+			utils.LogInfo(fmt.Sprintf("%-11s %s", label, name))
+		}
+		for _, note := range r.Notes {
+			utils.LogInfo(fmt.Sprintf("%-11s   [!] %s", "", note))
+		}
+	}
+
+	utils.LogInfo(fmt.Sprintf("%d created, %d overwritten, %d merged, %d skipped, %d failed (%d tạo mới, %d ghi đè, %d hợp nhất, %d bỏ qua, %d thất bại)",
+		counts["create"], counts["overwrite"], counts["merge"], counts["skip"], counts["failed"],
+		counts["create"], counts["overwrite"], counts["merge"], counts["skip"], counts["failed"]))
+	return counts["failed"]
+}
+
+// analyzeFile parses the input according to its extension and runs the analyzer on it.
+// analyzeFile phân tích tệp đầu vào theo phần mở rộng và chạy bộ phân tích trên nó.
+func analyzeFile(ana *analyzer.AnalyzerService, inputFile string) ([]*models.ClassModel, error) {
+	switch strings.ToLower(filepath.Ext(inputFile)) {
+	case ".mmd", ".mermaid", ".md", ".markdown":
+		// Mermaid classDiagram (tệp .mmd hoặc khối ```mermaid trong Markdown)
+		content, err := ioutil.ReadFile(inputFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file (lỗi đọc tệp): %v", err)
+		}
+		return ana.AnalyzeMermaid(analyzer.ExtractMermaidBlocks(string(content)))
+	case ".puml", ".plantuml", ".pu", ".iuml", ".wsd":
+		// PlantUML (@startuml ... @enduml)
+		content, err := ioutil.ReadFile(inputFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file (lỗi đọc tệp): %v", err)
+		}
+		return ana.AnalyzePlantUML(analyzer.ExtractPlantUMLBlocks(string(content)))
+	default:
+		pages, err := models.ParseXML(inputFile)
+		if err != nil {
+			return nil, err
+		}
+		pages, err = models.SelectPages(pages, PageNames)
+		if err != nil {
+			return nil, err
+		}
+		for _, page := range pages {
+			utils.LogVerbose(fmt.Sprintf("Page (Trang): %s (%d cells)", page.Name, len(page.Cells())))
+		}
+		return ana.AnalyzePages(pages), nil
+	}
+}
+
+// isArchive reports whether an --output destination names an archive (.zip, .tar, .tar.gz or .tgz).
+// isArchive cho biết đích --output có phải là một tệp nén (.zip, .tar, .tar.gz hoặc .tgz) hay không.
+func isArchive(dest string) bool {
+	lower := strings.ToLower(dest)
+	for _, ext := range []string{".zip", ".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(lower, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// fixDiagram writes the fixes of the diagnostics back into the source .drawio file.
+// fixDiagram ghi các chỉnh sửa của chẩn đoán trở lại tệp .drawio nguồn.
+func fixDiagram(inputFile string, fixes []models.Fix) error {
+	switch ext := strings.ToLower(filepath.Ext(inputFile)); ext {
+	case ".mmd", ".mermaid", ".md", ".markdown", ".puml", ".plantuml", ".pu", ".iuml", ".wsd", ".svg", ".png":
+		return fmt.Errorf("--fix only rewrites .drawio files, not %s (--fix chỉ ghi lại tệp .drawio)", ext)
+	}
+	if len(fixes) == 0 {
+		utils.LogInfo("Nothing to fix (Không có gì cần sửa)")
+		return nil
+	}
 
-	var overallReport strings.Builder
-	overallReport.WriteString("# Generation Report (Báo cáo tạo code)\n\n")
+	info, err := os.Stat(inputFile)
+	if err != nil {
+		return fmt.Errorf("error reading file (lỗi đọc tệp): %v", err)
+	}
+	data, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		return fmt.Errorf("error reading file (lỗi đọc tệp): %v", err)
+	}
+	fixed, applied, err := models.ApplyFixes(data, fixes)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(inputFile, fixed, info.Mode()); err != nil {
+		return fmt.Errorf("error writing file (lỗi ghi tệp): %v", err)
+	}
+	utils.LogInfo(fmt.Sprintf("Fixed (Đã sửa) %d item(s) in %s", applied, inputFile))
+	return nil
+}
 
+// check generates every class in memory and prints, in class order, a unified diff for each file that
+// differs from the one on disk under root, the --output folder (with -m, from the merged content).
+// Nothing is written; returns 1 when anything differs.
+// check tạo mọi lớp trong bộ nhớ và in, theo thứ tự các lớp, unified diff cho mỗi tệp khác với tệp
+// trên đĩa bên dưới root, thư mục --output (với -m, so với nội dung đã hợp nhất). Không ghi gì cả;
+// trả về 1 khi có khác biệt.
+func check(gen generator.CodeGenerator, merger *generator.JavaMerger, classes []*models.ClassModel, root string) int {
+	if root == "-" || isArchive(root) {
+		utils.LogInfo("Error: --check compares with a folder, not stdout or an archive (Lỗi: --check so sánh với một thư mục, không phải stdout hay tệp nén)")
+		return 1
+	}
+	// Đọc qua cùng thư mục gốc mà sink dùng khi ghi
+	sink := output.NewFileSink(root)
+
+	var artifacts []*generator.GeneratedArtifact
+	failed := 0
 	for _, cls := range classes {
 		artifact, err := gen.Generate(cls)
 		if err != nil {
 			utils.LogInfo(fmt.Sprintf("Failed to generate code for (Không thể tạo code cho) %s: %v", cls.Name, err))
+			failed++
 			continue
 		}
-
-		// Handle File Writing
-		// Xử lý ghi tệp
-		finalPath := artifact.FileName
-		// Check overwrite
-		// Kiểm tra ghi đè
-		skip := false
-		if !OverwriteMode {
-			if _, err := os.Stat(finalPath); err == nil {
-				utils.LogVerbose(fmt.Sprintf("Skipped (Đã bỏ qua) %s (exists, use -o to overwrite)", finalPath))
-				overallReport.WriteString(fmt.Sprintf("# %s [Skipped (Đã bỏ qua)]\n- File exists and -o not set.\n\n", cls.Name))
-				skip = true
+		artifacts = append(artifacts, artifact)
+	}
+	changed := 0
+	for _, artifact := range artifacts {
+		path := filepath.ToSlash(filepath.Join(root, artifact.FileName))
+		oldName, expected := "a/"+path, artifact.Content
+		existing, err := sink.ReadFile(artifact.FileName)
+		if err != nil {
+			oldName = "/dev/null"
+		} else if MergeMode {
+			merged, _, err := merger.Merge(string(existing), artifact.Content)
+			if err != nil {
+				utils.LogInfo(fmt.Sprintf("Failed to merge (Không thể hợp nhất) %s: %v", path, err))
+				failed++
+				continue
 			}
+			expected = merged
 		}
 
-		if !skip {
-			f, err := os.Create(finalPath)
-			if err != nil {
-				utils.LogInfo(fmt.Sprintf("Failed to create file (Không thể tạo tệp) %s: %v", finalPath, err))
-			} else {
-				f.WriteString(artifact.Content)
-				f.Close()
-				utils.LogVerbose(fmt.Sprintf("Generated (Đã tạo) %s", finalPath))
-				overallReport.WriteString(artifact.ReportEntry)
-			}
+		if diff := utils.UnifiedDiff(oldName, "b/"+path, string(existing), expected, 3); diff != "" {
+			changed++
+			utils.LogVerbose(fmt.Sprintf("Out of date (Đã lỗi thời) %s", path))
+			fmt.Print(diff)
 		}
 	}
 
-	// Save Report
-	// Lưu báo cáo
-	if !NoReportMode {
-		ioutil.WriteFile("Report.md", []byte(overallReport.String()), 0644)
-		utils.LogInfo("Generated Report.md")
+	utils.LogInfo(fmt.Sprintf("%d of %d file(s) out of date, %d failed (%d/%d tệp lỗi thời, %d thất bại)", changed, len(artifacts), failed, changed, len(artifacts), failed))
+	if changed > 0 || failed > 0 {
+		return 1
 	}
+	return 0
+}
 
-	// 4. Finalize
-	// 4. Hoàn tất
-	// utils.WriteLog() // Removed as per user request
+// validate prints the diagnostics of the analysis in the selected format and returns the exit code:
+// 1 when there are errors.
+// validate in các chẩn đoán của quá trình phân tích theo định dạng đã chọn và trả về mã thoát:
+// 1 khi có lỗi.
+func validate(inputFile string, diagnostics *models.Diagnostics) int {
+	var exp exporter.DiagnosticExporter
+	switch OutputFormat {
+	case "json":
+		exp = exporter.NewJSONExporter()
+	case "sarif":
+		exp = exporter.NewSARIFExporter()
+	}
+
+	if exp != nil {
+		data, err := exp.Export(inputFile, diagnostics.List())
+		if err != nil {
+			utils.LogInfo(fmt.Sprintf("Error (Lỗi): %v", err))
+			return 1
+		}
+		os.Stdout.Write(data)
+	} else {
+		for _, d := range diagnostics.List() {
+			color := utils.Yellow
+			switch d.Severity {
+			case models.SeverityError:
+				color = utils.Red
+			case models.SeverityNote:
+				color = utils.Cyan
+			}
+			utils.LogInfo(color + d.String() + utils.Reset)
+		}
+	}
+
+	errors, warnings, notes := diagnostics.Count(models.SeverityError), diagnostics.Count(models.SeverityWarning), diagnostics.Count(models.SeverityNote)
+	utils.LogInfo(fmt.Sprintf("%d error(s), %d warning(s), %d note(s) (%d lỗi, %d cảnh báo, %d ghi chú)", errors, warnings, notes, errors, warnings, notes))
+	if diagnostics.HasErrors() {
+		return 1
+	}
+	return 0
 }
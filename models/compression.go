@@ -0,0 +1,79 @@
+package models
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+)
+
+// IsCompressedDiagram reports whether the diagram body holds a compressed payload instead of an mxGraphModel.
+// IsCompressedDiagram cho biết thân biểu đồ có chứa dữ liệu nén thay vì mxGraphModel hay không.
+func IsCompressedDiagram(content string) bool {
+	trimmed := strings.TrimSpace(content)
+	return trimmed != "" && !strings.HasPrefix(trimmed, "<")
+}
+
+// DecompressDiagram decodes a draw.io compressed diagram (base64 -> raw deflate -> URL-encoded XML).
+// DecompressDiagram giải mã một biểu đồ draw.io đã nén (base64 -> raw deflate -> XML đã mã hóa URL).
+func DecompressDiagram(content string) ([]byte, error) {
+	// draw.io có thể chèn xuống dòng vào chuỗi base64, loại bỏ tất cả khoảng trắng trước khi giải mã
+	cleaned := strings.Join(strings.Fields(content), "")
+
+	data, err := base64.StdEncoding.DecodeString(cleaned)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 payload (dữ liệu base64 không hợp lệ): %v", err)
+	}
+
+	// Raw deflate (không có header zlib), giống pako.inflateRaw trong draw.io
+	reader := flate.NewReader(bytes.NewReader(data))
+	defer reader.Close()
+	inflated, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deflate payload (dữ liệu deflate không hợp lệ): %v", err)
+	}
+
+	// draw.io gọi encodeURIComponent trước khi nén. Các phiên bản cũ không làm vậy,
+	// nên nếu giải mã URL thất bại thì giữ nguyên dữ liệu.
+	decoded, err := url.PathUnescape(string(inflated))
+	if err != nil {
+		return inflated, nil
+	}
+	return []byte(decoded), nil
+}
+
+// CompressDiagram encodes a diagram the way draw.io does (URL-encoded XML -> raw deflate -> base64).
+// CompressDiagram mã hóa một biểu đồ theo cách của draw.io (XML mã hóa URL -> raw deflate -> base64).
+func CompressDiagram(xmlData []byte) (string, error) {
+	var buf bytes.Buffer
+	writer, err := flate.NewWriter(&buf, flate.BestCompression)
+	if err != nil {
+		return "", fmt.Errorf("error creating deflate writer (lỗi tạo bộ nén deflate): %v", err)
+	}
+	if _, err := writer.Write([]byte(encodeURIComponent(string(xmlData)))); err != nil {
+		return "", fmt.Errorf("error compressing diagram (lỗi nén biểu đồ): %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("error compressing diagram (lỗi nén biểu đồ): %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// encodeURIComponent escapes a string like the JavaScript function of the same name.
+// encodeURIComponent thoát một chuỗi giống hàm JavaScript cùng tên.
+func encodeURIComponent(s string) string {
+	const unreserved = "-_.!~*'()"
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte(unreserved, c) != -1 {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// compressedModel is the payload draw.io writes for compressedXML.
+// compressedModel là dữ liệu mà draw.io ghi cho compressedXML.
+const (
+	compressedXML   = `<mxGraphModel><root><mxCell id="0"/><mxCell id="c1" value="User &amp; Co" vertex="1"/></root></mxGraphModel>`
+	compressedModel = "UzV2zq1wL0osyPDNT0nNUTV2VTV2LsrPL4GwciucU3NyVI0MMlNUjV1UjYwMgFjVyA2HbLIhWNqgLDGnNBUiFFqcWqQK0maWmFugauwEZDvnQ1WlFpWkVkCUGSKbC2QgnADkoLkQAA=="
+	// Các phiên bản draw.io cũ nén XML mà không mã hóa URL trước
+	legacyModel = "s8mtcC9KLMjwzU9JzbGzKcrPL7Gzya1wTs3JUchMsVUyUNJH4ScbKimUJeaUptoqhRanFimoJeYWWCs45wNFU4tKUitslQxBOvQhBumjmA4A"
+)
+
+func TestDecompressDiagram(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{name: "url-encoded payload", content: compressedModel, want: compressedXML},
+		{name: "payload wrapped on several lines", content: "\n  " + compressedModel[:40] + "\n  " + compressedModel[40:] + "\n", want: compressedXML},
+		{name: "legacy payload without url encoding", content: legacyModel, want: compressedXML},
+		{name: "invalid base64", content: "not base64!", wantErr: true},
+		{name: "invalid deflate", content: "AAAA", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecompressDiagram(tt.content)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("DecompressDiagram() = %s, want an error", got)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Errorf("DecompressDiagram() = %s, %v; want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompressDiagramRoundTrip(t *testing.T) {
+	for _, xml := range []string{compressedXML, "<a>ünïcode 100% ~*'()</a>", ""} {
+		payload, err := CompressDiagram([]byte(xml))
+		if err != nil {
+			t.Fatalf("CompressDiagram(%q) failed: %v", xml, err)
+		}
+		got, err := DecompressDiagram(payload)
+		if err != nil || string(got) != xml {
+			t.Errorf("round trip of %q = %q, %v", xml, got, err)
+		}
+	}
+}
+
+func TestIsCompressedDiagram(t *testing.T) {
+	tests := map[string]bool{
+		compressedModel:            true,
+		"  <mxGraphModel/>":        false,
+		"\n\t ":                    false,
+		"\n" + legacyModel + "\n ": true,
+	}
+	for content, want := range tests {
+		if got := IsCompressedDiagram(content); got != want {
+			t.Errorf("IsCompressedDiagram(%q) = %v, want %v", content, got, want)
+		}
+	}
+}
+
+func TestParseXMLCompressedPages(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "compressed.drawio")
+	data := `<mxfile><diagram name="Main">` + compressedModel + `</diagram><diagram>` + compressedXML + `</diagram></mxfile>`
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	pages, err := ParseXML(file)
+	if err != nil {
+		t.Fatalf("ParseXML failed: %v", err)
+	}
+	if len(pages) != 2 || pages[0].Name != "Main" || pages[1].Name != "Page-2" {
+		t.Fatalf("pages = %+v, want Main and Page-2", pages)
+	}
+	for _, page := range pages {
+		cells := page.Cells()
+		if len(cells) != 2 || cells[1].Value != "User & Co" || cells[1].Page != page.Name {
+			t.Errorf("cells of %s = %+v, want c1 with value \"User & Co\"", page.Name, cells)
+		}
+	}
+}
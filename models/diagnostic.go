@@ -0,0 +1,191 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Severity defines how serious a diagnostic is.
+// Severity định nghĩa mức độ nghiêm trọng của một chẩn đoán.
+type Severity string
+
+const (
+	// SeverityError marks a problem that prevents valid Java from being generated.
+	// SeverityError đánh dấu vấn đề khiến không thể sinh mã Java hợp lệ.
+	SeverityError Severity = "error"
+
+	// SeverityWarning marks a problem that was auto-corrected or guessed.
+	// SeverityWarning đánh dấu vấn đề đã được tự động sửa hoặc phỏng đoán.
+	SeverityWarning Severity = "warning"
+
+	// SeverityNote marks notation that is understood but not standard UML.
+	// SeverityNote đánh dấu ký pháp được hiểu nhưng không phải UML chuẩn.
+	SeverityNote Severity = "note"
+)
+
+// Rule identifiers reported by the analyzer.
+// Các định danh quy tắc được bộ phân tích báo cáo.
+const (
+	RuleDuplicateClass     = "duplicate-class"
+	RuleUntypedField       = "untyped-field"
+	RuleEnumMethodConflict = "enum-method-conflict"
+	RuleExtendsEnum        = "extends-enum"
+	RuleMultipleExtends    = "multiple-extends"
+	RuleExtendsInterface   = "extends-interface"
+	RuleImplementsClass    = "implements-class"
+	RuleInheritanceCycle   = "inheritance-cycle"
+	RuleReturnTypeClash    = "return-type-clash"
+	RuleStereotypeTypo     = "stereotype-typo"
+	RuleMemberNotation     = "member-notation"
+)
+
+// Rule describes one check of the analyzer and the severity it reports with.
+// Rule mô tả một kiểm tra của bộ phân tích và mức độ nghiêm trọng mà nó báo cáo.
+type Rule struct {
+	ID          string   // Rule identifier // Định danh quy tắc
+	Severity    Severity // Default severity // Mức độ nghiêm trọng mặc định
+	Description string   // Short description // Mô tả ngắn gọn
+}
+
+// Rules lists every rule of the analyzer, used by machine readable reports.
+// Rules liệt kê mọi quy tắc của bộ phân tích, dùng cho các báo cáo máy đọc được.
+var Rules = []Rule{
+	{RuleDuplicateClass, SeverityError, "Two classes on the same page share a package and a name."},
+	{RuleUntypedField, SeverityWarning, "A field has no type; String is assumed."},
+	{RuleEnumMethodConflict, SeverityError, "An enum method clashes with, or looks like, an enum constant."},
+	{RuleExtendsEnum, SeverityError, "A class extends an enum, which Java does not allow."},
+	{RuleMultipleExtends, SeverityError, "A class extends more than one class."},
+	{RuleExtendsInterface, SeverityWarning, "A class extends an interface; the edge is treated as implements."},
+	{RuleImplementsClass, SeverityWarning, "A class implements a class; the edge is treated as extends."},
+	{RuleInheritanceCycle, SeverityError, "Classes inherit from each other in a cycle."},
+	{RuleReturnTypeClash, SeverityError, "An override has a return type incompatible with the inherited method."},
+	{RuleStereotypeTypo, SeverityWarning, "A misspelled stereotype was recognised, e.g. <<Iterface>>."},
+	{RuleMemberNotation, SeverityNote, "A member is written in Java notation instead of UML (name: Type)."},
+}
+
+// Diagnostic is one problem found in a diagram, located by page and mxCell id.
+// Diagnostic là một vấn đề được tìm thấy trong biểu đồ, được định vị theo trang và id của mxCell.
+type Diagnostic struct {
+	Severity Severity `json:"severity"`       // Error or warning // Lỗi hoặc cảnh báo
+	RuleID   string   `json:"ruleId"`         // Stable rule identifier, e.g. "extends-enum" // Định danh quy tắc ổn định, ví dụ "extends-enum"
+	CellID   string   `json:"cellId"`         // mxCell id (class name for Mermaid/PlantUML) // id của mxCell (tên lớp với Mermaid/PlantUML)
+	Page     string   `json:"page,omitempty"` // Page (tab) name, empty for text sources // Tên trang (tab), rỗng với nguồn văn bản
+	Message  string   `json:"message"`        // Human readable description // Mô tả dễ đọc
+	Fix      *Fix     `json:"fix,omitempty"`  // Edit that makes the diagram match what was understood // Chỉnh sửa giúp biểu đồ khớp với những gì đã hiểu
+}
+
+// Fix is a targeted edit of one attribute of one cell: "style" or "value" (the label).
+// Fix là một chỉnh sửa có mục tiêu cho một thuộc tính của một ô: "style" hoặc "value" (nhãn).
+type Fix struct {
+	Page      string `json:"page,omitempty"` // Page (tab) name // Tên trang (tab)
+	CellID    string `json:"cellId"`         // mxCell (or UserObject) id // id của mxCell (hoặc UserObject)
+	Attribute string `json:"attribute"`      // "style" or "value" // "style" hoặc "value"
+	Value     string `json:"value"`          // New attribute value // Giá trị mới của thuộc tính
+}
+
+// String formats the diagnostic as "error [rule] Page#cell: message".
+// String định dạng chẩn đoán thành "error [rule] Page#cell: message".
+func (d Diagnostic) String() string {
+	location := d.CellID
+	if d.Page != "" {
+		location = d.Page + "#" + d.CellID
+	}
+	return fmt.Sprintf("%s [%s] %s: %s", d.Severity, d.RuleID, location, d.Message)
+}
+
+// Diagnostics collects the diagnostics reported while analyzing a diagram.
+// A nil collector discards everything, so extractors can be used without one.
+// Diagnostics thu thập các chẩn đoán được báo cáo trong khi phân tích biểu đồ.
+// Bộ thu thập nil bỏ qua mọi thứ, nhờ đó các bộ trích xuất có thể dùng mà không cần nó.
+type Diagnostics struct {
+	items []Diagnostic
+}
+
+// NewDiagnostics creates an empty collector.
+// NewDiagnostics tạo một bộ thu thập rỗng.
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{}
+}
+
+// Report adds a diagnostic. Reporting the same diagnostic twice keeps a single copy.
+// Report thêm một chẩn đoán. Báo cáo cùng một chẩn đoán hai lần chỉ giữ một bản.
+func (ds *Diagnostics) Report(severity Severity, ruleID, cellID, page, message string) {
+	ds.add(Diagnostic{Severity: severity, RuleID: ruleID, CellID: cellID, Page: page, Message: message})
+}
+
+// ReportFix adds a diagnostic together with the edit that fixes it in the diagram.
+// ReportFix thêm một chẩn đoán cùng với chỉnh sửa giúp sửa nó trong biểu đồ.
+func (ds *Diagnostics) ReportFix(severity Severity, ruleID, cellID, page, message string, fix Fix) {
+	ds.add(Diagnostic{Severity: severity, RuleID: ruleID, CellID: cellID, Page: page, Message: message, Fix: &fix})
+}
+
+// add stores a diagnostic unless an identical one (ignoring the fix) is already stored.
+// add lưu một chẩn đoán trừ khi đã có một chẩn đoán giống hệt (bỏ qua phần sửa).
+func (ds *Diagnostics) add(d Diagnostic) {
+	if ds == nil {
+		return
+	}
+	for _, existing := range ds.items {
+		if existing.Severity == d.Severity && existing.RuleID == d.RuleID && existing.CellID == d.CellID &&
+			existing.Page == d.Page && existing.Message == d.Message {
+			return
+		}
+	}
+	ds.items = append(ds.items, d)
+}
+
+// Fixes returns the edits attached to the diagnostics, in the order of List.
+// Fixes trả về các chỉnh sửa gắn với các chẩn đoán, theo thứ tự của List.
+func (ds *Diagnostics) Fixes() []Fix {
+	var fixes []Fix
+	for _, d := range ds.List() {
+		if d.Fix != nil {
+			fixes = append(fixes, *d.Fix)
+		}
+	}
+	return fixes
+}
+
+// List returns the diagnostics ordered by page, cell, rule and message.
+// List trả về các chẩn đoán được sắp theo trang, ô, quy tắc và thông điệp.
+func (ds *Diagnostics) List() []Diagnostic {
+	if ds == nil {
+		return nil
+	}
+	list := append([]Diagnostic(nil), ds.items...)
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if a.Page != b.Page {
+			return a.Page < b.Page
+		}
+		if a.CellID != b.CellID {
+			return a.CellID < b.CellID
+		}
+		if a.RuleID != b.RuleID {
+			return a.RuleID < b.RuleID
+		}
+		return a.Message < b.Message
+	})
+	return list
+}
+
+// Count returns the number of diagnostics with the given severity.
+// Count trả về số chẩn đoán có mức độ nghiêm trọng đã cho.
+func (ds *Diagnostics) Count(severity Severity) int {
+	if ds == nil {
+		return 0
+	}
+	n := 0
+	for _, d := range ds.items {
+		if d.Severity == severity {
+			n++
+		}
+	}
+	return n
+}
+
+// HasErrors reports whether at least one error was collected.
+// HasErrors cho biết đã thu thập được ít nhất một lỗi hay chưa.
+func (ds *Diagnostics) HasErrors() bool {
+	return ds.Count(SeverityError) > 0
+}
@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // ClassType defines the type of the class (Class, Interface, Enum, etc.).
 // ClassType định nghĩa loại của lớp (Lớp, Giao diện, Enum, v.v.).
 type ClassType string
@@ -29,26 +31,72 @@ const (
 // Field represents a field (attribute) in a class.
 // Field đại diện cho một trường (thuộc tính) trong một lớp.
 type Field struct {
-	Original     string // Original string from diagram // Chuỗi gốc từ biểu đồ
-	Name         string // Name of the field // Tên của trường
-	Type         string // Data type of the field // Kiểu dữ liệu của trường
-	Visibility   string // Access modifier (public, private, etc.) // Phạm vi truy cập (public, private, v.v.)
-	IsStatic     bool   // Is the field static? // Trường có phải là tĩnh không?
-	IsFinal      bool   // Is the field final? // Trường có phải là hằng số không?
-	InitialValue string // Initial value of the field // Giá trị khởi tạo của trường
+	Original     string   // Original string from diagram // Chuỗi gốc từ biểu đồ
+	Name         string   // Name of the field // Tên của trường
+	Type         string   // Data type of the field // Kiểu dữ liệu của trường
+	Visibility   string   // Access modifier (public, private, etc.) // Phạm vi truy cập (public, private, v.v.)
+	IsStatic     bool     // Is the field static? // Trường có phải là tĩnh không?
+	IsFinal      bool     // Is the field final? // Trường có phải là hằng số không?
+	InitialValue string   // Initial value of the field // Giá trị khởi tạo của trường
+	Annotations  []string // Annotations from shape metadata (e.g. @Column) // Các chú thích từ siêu dữ liệu hình (ví dụ @Column)
+	Multiplicity string   // Multiplicity of an association end (0..1, *) // Bội số của đầu liên kết (0..1, *)
+}
+
+// IsEnumConstant reports whether a field of an enum is one of its constants: constants are written
+// without a type or visibility marker (RED), while "-code: int" is a regular field.
+// IsEnumConstant cho biết một trường của enum có phải là hằng số của nó hay không: hằng số được viết
+// không có kiểu hoặc ký hiệu phạm vi (RED), còn "-code: int" là một trường thông thường.
+func (f Field) IsEnumConstant() bool {
+	return !strings.Contains(f.Original, ":") && !strings.HasPrefix(f.Original, "-") && !strings.HasPrefix(f.Original, "#") && !strings.HasPrefix(f.Original, "+")
 }
 
 // Method represents a method (function) in a class.
 // Method đại diện cho một phương thức (hàm) trong một lớp.
 type Method struct {
-	Original   string // Original string from diagram // Chuỗi gốc từ biểu đồ
-	Name       string // Name of the method // Tên của phương thức
-	Parameters string // Parameters of the method // Các tham số của phương thức
-	ReturnType string // Return type of the method // Kiểu trả về của phương thức
-	Visibility string // Access modifier // Phạm vi truy cập
-	IsStatic   bool   // Is the method static? // Phương thức có phải là tĩnh không?
-	IsAbstract bool   // Is the method abstract? // Phương thức có phải là trừu tượng không?
-	IsOverride bool   // Is the method overriding a parent method? // Phương thức có ghi đè phương thức cha không?
+	Original    string      // Original string from diagram // Chuỗi gốc từ biểu đồ
+	Name        string      // Name of the method // Tên của phương thức
+	Parameters  []Parameter // Parameters of the method // Các tham số của phương thức
+	ReturnType  string      // Return type of the method // Kiểu trả về của phương thức
+	Visibility  string      // Access modifier // Phạm vi truy cập
+	IsStatic    bool        // Is the method static? // Phương thức có phải là tĩnh không?
+	IsAbstract  bool        // Is the method abstract? // Phương thức có phải là trừu tượng không?
+	IsOverride  bool        // Is the method overriding a parent method? // Phương thức có ghi đè phương thức cha không?
+	Annotations []string    // Annotations from shape metadata // Các chú thích từ siêu dữ liệu hình
+	Throws      []string    // Declared checked exceptions // Các ngoại lệ được khai báo
+}
+
+// Parameter represents a single method parameter.
+// Parameter đại diện cho một tham số của phương thức.
+type Parameter struct {
+	Name    string // Name of the parameter (may be empty on the diagram) // Tên của tham số (có thể trống trên biểu đồ)
+	Type    string // Java type, normalized by ParseType when valid // Kiểu Java, được chuẩn hóa bởi ParseType khi hợp lệ
+	Default string // Default value written on the diagram ("= 10") // Giá trị mặc định được ghi trên biểu đồ ("= 10")
+}
+
+// TypeParameter represents a generic type parameter of a class, e.g. ID extends Serializable.
+// TypeParameter đại diện cho một tham số kiểu tổng quát của lớp, ví dụ ID extends Serializable.
+type TypeParameter struct {
+	Name   string   // Name of the parameter (T, ID) // Tên của tham số (T, ID)
+	Bounds []string // Upper bounds joined with & in Java // Các cận trên, nối bằng & trong Java
+}
+
+// String renders the parameter in Java syntax.
+// String hiển thị tham số theo cú pháp Java.
+func (tp TypeParameter) String() string {
+	if len(tp.Bounds) == 0 {
+		return tp.Name
+	}
+	return tp.Name + " extends " + strings.Join(tp.Bounds, " & ")
+}
+
+// Signature returns the method name followed by its parameter types, e.g. put(String,List<Integer>).
+// Signature trả về tên phương thức kèm theo các kiểu tham số, ví dụ put(String,List<Integer>).
+func (m Method) Signature() string {
+	var types []string
+	for _, p := range m.Parameters {
+		types = append(types, p.Type)
+	}
+	return m.Name + "(" + strings.Join(types, ",") + ")"
 }
 
 // ClassModel represents the semantic model of a class/interface parsed from the diagram.
@@ -56,13 +104,121 @@ type Method struct {
 // Previously known as JavaClass.
 // Trước đây được gọi là JavaClass.
 type ClassModel struct {
-	ID         string    // Unique ID from the diagram // ID duy nhất từ biểu đồ
-	Name       string    // Cleaned name of the class // Tên đã làm sạch của lớp
-	RawName    string    // Raw name from the diagram (for reference) // Tên gốc từ biểu đồ (để tham khảo)
-	Type       ClassType // The type of the construct (Class, Interface, etc.) // Loại cấu trúc (Lớp, Giao diện, v.v.)
-	Extends    string    // Name of the parent class // Tên của lớp cha
-	Implements []string  // List of implemented interfaces // Danh sách các giao diện được triển khai
-	Fields     []Field   // List of fields // Danh sách các trường
-	Methods    []Method  // List of methods // Danh sách các phương thức
-	LogEntries []string  // Log entries specific to this class // Các mục nhật ký cụ thể cho lớp này
+	ID             string          // Unique ID from the diagram // ID duy nhất từ biểu đồ
+	Name           string          // Cleaned name of the class // Tên đã làm sạch của lớp
+	RawName        string          // Raw name from the diagram (for reference) // Tên gốc từ biểu đồ (để tham khảo)
+	Page           string          // Name of the diagram page defining the class // Tên trang biểu đồ định nghĩa lớp
+	Package        string          // Java package of the class (e.g. com.acme.billing) // Gói Java của lớp (ví dụ com.acme.billing)
+	Type           ClassType       // The type of the construct (Class, Interface, etc.) // Loại cấu trúc (Lớp, Giao diện, v.v.)
+	Stereotype     string          // Stereotype written on the class, e.g. exception, Entity // Khuôn mẫu ghi trên lớp, ví dụ exception, Entity
+	TypeParameters []TypeParameter // Generic parameters, e.g. <T, ID extends Serializable> // Tham số kiểu tổng quát, ví dụ <T, ID extends Serializable>
+	Fields         []Field         // List of fields // Danh sách các trường
+	Methods        []Method        // List of methods // Danh sách các phương thức
+	Relationships  []Relationship  // Edges starting at this class // Các cạnh bắt đầu từ lớp này
+	LogEntries     []string        // Log entries specific to this class // Các mục nhật ký cụ thể cho lớp này
+	Position       int             // Reading order in the source: page, then top to bottom and left to right; declaration order in text // Thứ tự đọc trong nguồn: trang, rồi từ trên xuống và trái sang phải; thứ tự khai báo với văn bản
+
+	// Properties holds custom shape metadata (draw.io "Edit Data"), e.g. package, table, annotations.
+	// Properties chứa siêu dữ liệu tùy chỉnh của hình (draw.io "Edit Data"), ví dụ package, table, annotations.
+	Properties  map[string]string
+	Annotations []string // Class-level annotations (e.g. @Entity) // Các chú thích cấp lớp (ví dụ @Entity)
+}
+
+// ExceptionBase returns the superclass implied by an exception stereotype: Exception for <<exception>>,
+// RuntimeException for <<runtime exception>>, or "" for other classes.
+// ExceptionBase trả về lớp cha được ngụ ý bởi khuôn mẫu ngoại lệ: Exception với <<exception>>,
+// RuntimeException với <<runtime exception>>, hoặc "" với các lớp khác.
+func (c *ClassModel) ExceptionBase() string {
+	switch strings.Join(strings.Fields(strings.ToLower(c.Stereotype)), "") {
+	case "exception", "checkedexception":
+		return "Exception"
+	case "runtimeexception", "uncheckedexception":
+		return "RuntimeException"
+	}
+	return ""
+}
+
+// RelationshipKind defines the UML kind of an edge between two classes.
+// RelationshipKind định nghĩa loại UML của một cạnh giữa hai lớp.
+type RelationshipKind string
+
+const (
+	// Generalization is a class extending a class (or an interface extending an interface).
+	// Generalization là một lớp kế thừa một lớp (hoặc giao diện kế thừa giao diện).
+	Generalization RelationshipKind = "generalization"
+
+	// Realization is a class implementing an interface.
+	// Realization là một lớp triển khai một giao diện.
+	Realization RelationshipKind = "realization"
+
+	// Association is a plain (optionally navigable) link between two classes.
+	// Association là một liên kết thông thường (có thể có hướng) giữa hai lớp.
+	Association RelationshipKind = "association"
+
+	// Aggregation is a whole/part link drawn with a hollow diamond.
+	// Aggregation là liên kết tổng thể/bộ phận được vẽ bằng hình thoi rỗng.
+	Aggregation RelationshipKind = "aggregation"
+
+	// Composition is a whole/part link drawn with a filled diamond.
+	// Composition là liên kết tổng thể/bộ phận được vẽ bằng hình thoi đặc.
+	Composition RelationshipKind = "composition"
+
+	// Dependency is a dashed "uses" arrow; it generates no code.
+	// Dependency là mũi tên "sử dụng" nét đứt; không sinh mã.
+	Dependency RelationshipKind = "dependency"
+)
+
+// RelationshipEnd holds the multiplicity, role name and navigability of one end of an edge.
+// RelationshipEnd chứa bội số, tên vai trò và khả năng điều hướng của một đầu của cạnh.
+type RelationshipEnd struct {
+	Multiplicity string // e.g. 1, 0..1, 1..*, * // ví dụ 1, 0..1, 1..*, *
+	Role         string // Role name, used as the field name // Tên vai trò, dùng làm tên trường
+	Navigable    bool   // An open arrow points at this end // Có mũi tên mở chỉ vào đầu này
+}
+
+// Relationship represents an edge between two classes, as drawn on the diagram.
+// Relationship đại diện cho một cạnh giữa hai lớp, như được vẽ trên biểu đồ.
+type Relationship struct {
+	EdgeID        string           // ID of the edge cell // ID của ô cạnh
+	Page          string           // Page holding the edge // Trang chứa cạnh
+	Kind          RelationshipKind // Generalization, realization, association... // Kế thừa, triển khai, liên kết...
+	Source        string           // Name of the source class (the child for generalization) // Tên của lớp nguồn (lớp con đối với kế thừa)
+	Target        string           // Name of the target class // Tên của lớp đích
+	Label         string           // Label in the middle of the edge // Nhãn ở giữa cạnh
+	SourceEnd     RelationshipEnd  // Labels near the source // Các nhãn gần đầu nguồn
+	TargetEnd     RelationshipEnd  // Labels near the target // Các nhãn gần đầu đích
+	Style         string           // Raw draw.io style of the edge // Kiểu draw.io gốc của cạnh
+	TypeArguments []string         // Type arguments bound to the target, e.g. User in Base<User> // Đối số kiểu gán cho đích, ví dụ User trong Base<User>
+}
+
+// TargetType returns the target with its type arguments, e.g. Repository<User, Long>.
+// TargetType trả về đích kèm các đối số kiểu, ví dụ Repository<User, Long>.
+func (r Relationship) TargetType() string {
+	if len(r.TypeArguments) == 0 {
+		return r.Target
+	}
+	return r.Target + "<" + strings.Join(r.TypeArguments, ", ") + ">"
+}
+
+// Extends returns the name of the parent class, taken from the generalization relationships.
+// Extends trả về tên của lớp cha, lấy từ các quan hệ kế thừa.
+func (c *ClassModel) Extends() string {
+	for _, rel := range c.Relationships {
+		if rel.Kind == Generalization {
+			return rel.Target
+		}
+	}
+	return ""
+}
+
+// Implements returns the names of the implemented interfaces, taken from the realization relationships.
+// Implements trả về tên các giao diện được triển khai, lấy từ các quan hệ triển khai.
+func (c *ClassModel) Implements() []string {
+	var names []string
+	for _, rel := range c.Relationships {
+		if rel.Kind == Realization {
+			names = append(names, rel.Target)
+		}
+	}
+	return names
 }
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassModelRelationships(t *testing.T) {
+	cls := &ClassModel{Name: "UserRepository", Relationships: []Relationship{
+		{Kind: Association, Target: "User"},
+		{Kind: Realization, Target: "Auditable"},
+		{Kind: Generalization, Target: "BaseRepository", TypeArguments: []string{"User", "Long"}},
+		{Kind: Dependency, Target: "Clock"},
+		{Kind: Realization, Target: "Closeable"},
+	}}
+
+	if got := cls.Extends(); got != "BaseRepository" {
+		t.Errorf("Extends() = %q, want BaseRepository", got)
+	}
+	if got, want := cls.Implements(), []string{"Auditable", "Closeable"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Implements() = %v, want %v", got, want)
+	}
+	// Chỉ quan hệ kế thừa và triển khai được tính, các liên kết khác thì không
+	if got := (&ClassModel{Relationships: []Relationship{{Kind: Composition, Target: "Line"}}}).Extends(); got != "" {
+		t.Errorf("Extends() of a composition = %q, want none", got)
+	}
+}
+
+func TestRelationshipTargetType(t *testing.T) {
+	tests := []struct {
+		rel  Relationship
+		want string
+	}{
+		{rel: Relationship{Target: "Shape"}, want: "Shape"},
+		{rel: Relationship{Target: "Repository", TypeArguments: []string{"User", "Long"}}, want: "Repository<User, Long>"},
+		{rel: Relationship{Target: "Source", TypeArguments: []string{"List<T>"}}, want: "Source<List<T>>"},
+	}
+	for _, tt := range tests {
+		if got := tt.rel.TargetType(); got != tt.want {
+			t.Errorf("TargetType() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestClassModelExceptionBase(t *testing.T) {
+	tests := map[string]string{
+		"exception":          "Exception",
+		"Checked Exception":  "Exception",
+		"runtime exception":  "RuntimeException",
+		"UncheckedException": "RuntimeException",
+		"Entity":             "",
+		"":                   "",
+	}
+	for stereotype, want := range tests {
+		if got := (&ClassModel{Stereotype: stereotype}).ExceptionBase(); got != want {
+			t.Errorf("ExceptionBase() of <<%s>> = %q, want %q", stereotype, got, want)
+		}
+	}
+}
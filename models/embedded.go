@@ -0,0 +1,140 @@
+package models
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+)
+
+// pngSignature is the 8-byte header every PNG file starts with.
+// pngSignature là phần đầu 8 byte mà mọi tệp PNG đều bắt đầu.
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// ExtractMxFile sniffs the input and returns the mxfile XML it carries.
+// Plain .drawio XML is returned unchanged; editable .drawio.svg and .drawio.png exports are unpacked.
+// ExtractMxFile nhận diện dữ liệu đầu vào và trả về XML mxfile mà nó chứa.
+// XML .drawio thuần được trả về nguyên vẹn; các tệp xuất .drawio.svg và .drawio.png có thể chỉnh sửa sẽ được giải nén.
+func ExtractMxFile(data []byte) ([]byte, error) {
+	if bytes.HasPrefix(data, pngSignature) {
+		return extractFromPNG(data)
+	}
+	if isSVG(data) {
+		return extractFromSVG(data)
+	}
+	return data, nil
+}
+
+// isSVG checks whether the root element of the document is <svg>.
+// isSVG kiểm tra xem phần tử gốc của tài liệu có phải là <svg> không.
+func isSVG(data []byte) bool {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start.Name.Local == "svg"
+		}
+	}
+}
+
+// extractFromSVG reads the mxfile stored in the "content" attribute of the <svg> root.
+// extractFromSVG đọc mxfile được lưu trong thuộc tính "content" của phần tử gốc <svg>.
+func extractFromSVG(data []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			return nil, fmt.Errorf("invalid SVG (SVG không hợp lệ): %v", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == "content" {
+				// Bộ giải mã XML đã xử lý các thực thể (&lt; &quot; ...) trong thuộc tính
+				return normalizeEmbedded(attr.Value)
+			}
+		}
+		return nil, fmt.Errorf("SVG has no embedded diagram, export it with \"Include a copy of my diagram\" (SVG không chứa biểu đồ nhúng)")
+	}
+}
+
+// extractFromPNG reads the mxfile stored in a tEXt or zTXt chunk of a PNG file.
+// extractFromPNG đọc mxfile được lưu trong đoạn tEXt hoặc zTXt của tệp PNG.
+func extractFromPNG(data []byte) ([]byte, error) {
+	pos := len(pngSignature)
+	// Mỗi đoạn (chunk): độ dài (4 byte) + loại (4 byte) + dữ liệu + CRC (4 byte)
+	for pos+8 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		chunkType := string(data[pos+4 : pos+8])
+		start := pos + 8
+		end := start + length
+		if length < 0 || end+4 > len(data) {
+			return nil, fmt.Errorf("truncated PNG chunk %q (đoạn PNG bị cắt)", chunkType)
+		}
+		chunk := data[start:end]
+		pos = end + 4
+
+		if chunkType == "IEND" {
+			break
+		}
+		if chunkType != "tEXt" && chunkType != "zTXt" {
+			continue
+		}
+
+		sep := bytes.IndexByte(chunk, 0)
+		if sep == -1 {
+			continue
+		}
+		keyword := string(chunk[:sep])
+		if keyword != "mxfile" && keyword != "mxGraphModel" {
+			continue
+		}
+
+		text := chunk[sep+1:]
+		if chunkType == "zTXt" {
+			// Byte đầu tiên là phương thức nén (0 = zlib/deflate)
+			if len(text) == 0 {
+				continue
+			}
+			reader, err := zlib.NewReader(bytes.NewReader(text[1:]))
+			if err != nil {
+				return nil, fmt.Errorf("invalid zTXt chunk (đoạn zTXt không hợp lệ): %v", err)
+			}
+			text, err = ioutil.ReadAll(reader)
+			reader.Close()
+			if err != nil && err != io.ErrUnexpectedEOF {
+				return nil, fmt.Errorf("invalid zTXt chunk (đoạn zTXt không hợp lệ): %v", err)
+			}
+		}
+		return normalizeEmbedded(string(text))
+	}
+	return nil, fmt.Errorf("PNG has no embedded diagram, export it with \"Include a copy of my diagram\" (PNG không chứa biểu đồ nhúng)")
+}
+
+// normalizeEmbedded URL-decodes the payload if needed and wraps a bare mxGraphModel into an mxfile.
+// normalizeEmbedded giải mã URL nếu cần và bọc mxGraphModel đơn lẻ vào một mxfile.
+func normalizeEmbedded(payload string) ([]byte, error) {
+	payload = strings.TrimSpace(payload)
+	if !strings.HasPrefix(payload, "<") {
+		decoded, err := url.PathUnescape(payload)
+		if err != nil {
+			return nil, fmt.Errorf("invalid embedded diagram (biểu đồ nhúng không hợp lệ): %v", err)
+		}
+		payload = strings.TrimSpace(decoded)
+	}
+
+	if strings.HasPrefix(payload, "<mxGraphModel") {
+		payload = "<mxfile><diagram>" + payload + "</diagram></mxfile>"
+	}
+	return []byte(payload), nil
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"hash/crc32"
+	"net/url"
+	"testing"
+)
+
+// pngChunk encodes one PNG chunk: length, type, data and CRC.
+// pngChunk mã hóa một đoạn PNG: độ dài, loại, dữ liệu và CRC.
+func pngChunk(chunkType string, data []byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, uint32(len(data)))
+	buf.WriteString(chunkType)
+	buf.Write(data)
+	binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(append([]byte(chunkType), data...)))
+	return buf.Bytes()
+}
+
+// pngFile builds a minimal PNG made of an IHDR chunk, the given chunks and IEND.
+// pngFile tạo một tệp PNG tối thiểu gồm đoạn IHDR, các đoạn đã cho và IEND.
+func pngFile(chunks ...[]byte) []byte {
+	data := append([]byte{}, pngSignature...)
+	data = append(data, pngChunk("IHDR", []byte{0, 0, 0, 1, 0, 0, 0, 1, 8, 2, 0, 0, 0})...)
+	for _, chunk := range chunks {
+		data = append(data, chunk...)
+	}
+	return append(data, pngChunk("IEND", nil)...)
+}
+
+// zlibBytes compresses data with zlib, as draw.io does for zTXt chunks.
+// zlibBytes nén dữ liệu bằng zlib, như draw.io làm với các đoạn zTXt.
+func zlibBytes(data string) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write([]byte(data))
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestExtractMxFile(t *testing.T) {
+	const (
+		mxfile     = `<mxfile><diagram name="Main"><mxGraphModel><root><mxCell id="0"/></root></mxGraphModel></diagram></mxfile>`
+		graphModel = `<mxGraphModel><root><mxCell id="0"/></root></mxGraphModel>`
+	)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr bool
+	}{
+		{name: "plain drawio", data: []byte(mxfile), want: mxfile},
+		{
+			name: "drawio.svg",
+			data: []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" content="&lt;mxfile&gt;&lt;diagram name=&quot;Main&quot;&gt;&lt;mxGraphModel&gt;&lt;root&gt;&lt;mxCell id=&quot;0&quot;/&gt;&lt;/root&gt;&lt;/mxGraphModel&gt;&lt;/diagram&gt;&lt;/mxfile&gt;"><rect/></svg>`),
+			want: mxfile,
+		},
+		{name: "svg without diagram", data: []byte(`<svg xmlns="http://www.w3.org/2000/svg"><rect/></svg>`), wantErr: true},
+		{
+			name: "png with tEXt chunk",
+			data: pngFile(pngChunk("tEXt", append([]byte("mxfile\x00"), url.PathEscape(mxfile)...))),
+			want: mxfile,
+		},
+		{
+			name: "png with zTXt chunk",
+			data: pngFile(pngChunk("zTXt", append([]byte("mxGraphModel\x00\x00"), zlibBytes(graphModel)...))),
+			want: "<mxfile><diagram>" + graphModel + "</diagram></mxfile>",
+		},
+		{
+			name:    "png without diagram",
+			data:    pngFile(pngChunk("tEXt", []byte("Software\x00draw.io"))),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractMxFile(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ExtractMxFile() = %s, want an error", got)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Errorf("ExtractMxFile() = %s, %v; want %s", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package models
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// attrEscaper escapes an attribute value the way draw.io writes it.
+// attrEscaper thoát giá trị thuộc tính theo cách draw.io ghi nó.
+var attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;", "\n", "&#xa;")
+
+// reXMLAttr matches an attribute inside the text of a start tag.
+// reXMLAttr khớp một thuộc tính bên trong văn bản của thẻ mở.
+var reXMLAttr = regexp.MustCompile(`\s([\w:.-]+)\s*=\s*("[^"]*"|'[^']*')`)
+
+// textEdit replaces data[start:end] with text.
+// textEdit thay thế data[start:end] bằng text.
+type textEdit struct {
+	start, end int
+	text       string
+}
+
+// ApplyFixes rewrites the attributes named by fixes in the content of a .drawio file and returns the new
+// content with the number of fixes applied. Only the start tags of the fixed cells change, so every other
+// attribute, the layout and the formatting are preserved; compressed pages are decompressed, edited and
+// compressed again.
+// ApplyFixes ghi lại các thuộc tính được nêu trong fixes trong nội dung của tệp .drawio và trả về nội dung
+// mới cùng số chỉnh sửa đã áp dụng. Chỉ thẻ mở của các ô được sửa thay đổi, nên mọi thuộc tính khác, bố cục
+// và định dạng được giữ nguyên; các trang nén được giải nén, chỉnh sửa rồi nén lại.
+func ApplyFixes(data []byte, fixes []Fix) ([]byte, int, error) {
+	byPage := make(map[string]map[string][]Fix)
+	for _, fix := range fixes {
+		if byPage[fix.Page] == nil {
+			byPage[fix.Page] = make(map[string][]Fix)
+		}
+		byPage[fix.Page][fix.CellID] = append(byPage[fix.Page][fix.CellID], fix)
+	}
+	return applyFixes(data, "", byPage)
+}
+
+// applyFixes edits one XML document; page is the page being edited when the document has no <diagram>
+// elements (the payload of a compressed page).
+// applyFixes chỉnh sửa một tài liệu XML; page là trang đang được chỉnh sửa khi tài liệu không có phần tử
+// <diagram> (dữ liệu của một trang nén).
+func applyFixes(data []byte, page string, byPage map[string]map[string][]Fix) ([]byte, int, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var edits []textEdit
+	applied := 0
+
+	pageIndex := 0
+	var pendingStyle []Fix // Kiểu của UserObject nằm trên mxCell con của nó
+	hasCells := false
+	var payloadStart, payloadEnd int
+	var payload string
+
+	for {
+		start := int(decoder.InputOffset())
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("error parsing XML (lỗi phân tích XML): %v", err)
+		}
+		end := int(decoder.InputOffset())
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "diagram":
+				pageIndex++
+				page = fmt.Sprintf("Page-%d", pageIndex)
+				if name := xmlAttr(t, "name"); name != "" {
+					page = name
+				}
+				hasCells, payload = false, ""
+			case "mxCell", "UserObject", "object":
+				hasCells = true
+				tag := string(data[start:end])
+				changed := tag
+				wrapper := t.Name.Local != "mxCell"
+				byAttr := make(map[string][]Fix)
+				for _, fix := range byPage[page][xmlAttr(t, "id")] {
+					attr := fix.Attribute
+					if wrapper && attr == "value" {
+						attr = "label"
+					}
+					byAttr[attr] = append(byAttr[attr], fix)
+				}
+				if wrapper {
+					pendingStyle = byAttr["style"]
+					delete(byAttr, "style")
+				} else if len(pendingStyle) > 0 {
+					byAttr["style"] = append(byAttr["style"], pendingStyle...)
+					pendingStyle = nil
+				}
+				var attrs []string
+				for attr := range byAttr {
+					attrs = append(attrs, attr)
+				}
+				sort.Strings(attrs)
+				for _, attr := range attrs {
+					value, n := composeFixes(xmlAttr(t, attr), byAttr[attr])
+					if n > 0 {
+						changed = setXMLAttr(changed, attr, value)
+						applied += n
+					}
+				}
+				if changed != tag {
+					edits = append(edits, textEdit{start, end, changed})
+				}
+			}
+		case xml.CharData:
+			if text := strings.TrimSpace(string(t)); text != "" && IsCompressedDiagram(text) {
+				payloadStart, payloadEnd, payload = start, end, text
+			}
+		case xml.EndElement:
+			if t.Name.Local != "diagram" || hasCells || payload == "" || len(byPage[page]) == 0 {
+				continue
+			}
+			// Trang nén: giải nén, sửa, rồi nén lại vào cùng vị trí
+			inner, err := DecompressDiagram(payload)
+			if err != nil {
+				return nil, 0, fmt.Errorf("error decompressing page %q (lỗi giải nén trang): %v", page, err)
+			}
+			fixed, n, err := applyFixes(inner, page, byPage)
+			if err != nil {
+				return nil, 0, err
+			}
+			if n == 0 {
+				continue
+			}
+			compressed, err := CompressDiagram(fixed)
+			if err != nil {
+				return nil, 0, err
+			}
+			edits = append(edits, textEdit{payloadStart, payloadEnd, compressed})
+			applied += n
+		}
+	}
+
+	// Áp dụng từ cuối lên đầu để các vị trí phía trước không bị dịch chuyển
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	out := append([]byte(nil), data...)
+	for _, e := range edits {
+		out = append(out[:e.start], append([]byte(e.text), out[e.end:]...)...)
+	}
+	return out, applied, nil
+}
+
+// xmlAttr returns the value of an attribute of a start element.
+// xmlAttr trả về giá trị của một thuộc tính của phần tử mở.
+func xmlAttr(t xml.StartElement, name string) string {
+	for _, attr := range t.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// setXMLAttr sets an attribute inside the text of a start tag, adding it before the closing bracket when missing.
+// setXMLAttr đặt một thuộc tính bên trong văn bản của thẻ mở, thêm nó trước dấu đóng khi chưa có.
+func setXMLAttr(tag, name, value string) string {
+	escaped := attrEscaper.Replace(value)
+	for _, loc := range reXMLAttr.FindAllStringSubmatchIndex(tag, -1) {
+		if tag[loc[2]:loc[3]] == name {
+			return tag[:loc[4]] + `"` + escaped + `"` + tag[loc[5]:]
+		}
+	}
+	if strings.HasSuffix(tag, "/>") {
+		return strings.TrimSuffix(tag, "/>") + ` ` + name + `="` + escaped + `"/>`
+	}
+	return strings.TrimSuffix(tag, ">") + ` ` + name + `="` + escaped + `">`
+}
+
+// composeFixes applies to an attribute value every fix computed from it. Each fix carries the whole new value,
+// so the part it changes (between the common prefix and suffix) is taken as an edit of the original; edits
+// that overlap an earlier one are left out. It returns the new value and the number of fixes applied.
+// composeFixes áp dụng lên giá trị thuộc tính mọi chỉnh sửa được tính từ nó. Mỗi chỉnh sửa mang toàn bộ giá trị
+// mới, nên phần nó thay đổi (giữa tiền tố và hậu tố chung) được xem là một thay đổi của giá trị gốc; các thay
+// đổi chồng lên thay đổi trước bị bỏ qua. Trả về giá trị mới và số chỉnh sửa đã áp dụng.
+func composeFixes(original string, fixes []Fix) (string, int) {
+	var edits []textEdit
+	applied := 0
+	for _, fix := range fixes {
+		prefix := 0
+		for prefix < len(original) && prefix < len(fix.Value) && original[prefix] == fix.Value[prefix] {
+			prefix++
+		}
+		suffix := 0
+		for suffix < len(original)-prefix && suffix < len(fix.Value)-prefix &&
+			original[len(original)-1-suffix] == fix.Value[len(fix.Value)-1-suffix] {
+			suffix++
+		}
+		edit := textEdit{prefix, len(original) - suffix, fix.Value[prefix : len(fix.Value)-suffix]}
+
+		conflict := false
+		for _, e := range edits {
+			// Hai thay đổi chồng nhau, hoặc cùng chèn vào một vị trí
+			if edit.start < e.end && e.start < edit.end || edit.start == e.start && (edit.start == edit.end || e.start == e.end) {
+				conflict = true
+				break
+			}
+		}
+		if conflict {
+			continue
+		}
+		edits = append(edits, edit)
+		applied++
+	}
+
+	// Áp dụng từ cuối lên đầu để các vị trí phía trước không bị dịch chuyển
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	value := original
+	for _, e := range edits {
+		value = value[:e.start] + e.text + value[e.end:]
+	}
+	return value, applied
+}
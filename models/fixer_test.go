@@ -0,0 +1,184 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComposeFixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		original string
+		values   []string // Giá trị mới mà mỗi chỉnh sửa mang theo
+		want     string
+		applied  int
+	}{
+		{
+			name:     "single fix",
+			original: "+ getName(): string",
+			values:   []string{"+ getName(): String"},
+			want:     "+ getName(): String",
+			applied:  1,
+		},
+		{
+			name:     "independent fixes compose",
+			original: "- nme: string",
+			values:   []string{"- name: string", "- nme: String"},
+			want:     "- name: String",
+			applied:  2,
+		},
+		{
+			name:     "overlapping fix is left out",
+			original: "- x: int",
+			values:   []string{"- y: int", "- z: int"},
+			want:     "- y: int",
+			applied:  1,
+		},
+		{
+			name:     "two insertions at the same place conflict",
+			original: "swimlane;",
+			values:   []string{"swimlane;html=1;", "swimlane;fontStyle=1;"},
+			want:     "swimlane;html=1;",
+			applied:  1,
+		},
+		{
+			name:     "insertions at both ends",
+			original: "edgeStyle=orthogonal",
+			values:   []string{"endArrow=block;edgeStyle=orthogonal", "edgeStyle=orthogonal;dashed=1"},
+			want:     "endArrow=block;edgeStyle=orthogonal;dashed=1",
+			applied:  2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var fixes []Fix
+			for _, v := range tt.values {
+				fixes = append(fixes, Fix{CellID: "c1", Attribute: "value", Value: v})
+			}
+			got, applied := composeFixes(tt.original, fixes)
+			if got != tt.want || applied != tt.applied {
+				t.Errorf("composeFixes() = %q, %d; want %q, %d", got, applied, tt.want, tt.applied)
+			}
+		})
+	}
+}
+
+func TestSetXMLAttr(t *testing.T) {
+	tests := []struct {
+		name  string
+		tag   string
+		attr  string
+		value string
+		want  string
+	}{
+		{
+			name:  "replaces an existing value",
+			tag:   `<mxCell id="c1" value="old" vertex="1">`,
+			attr:  "value",
+			value: "new",
+			want:  `<mxCell id="c1" value="new" vertex="1">`,
+		},
+		{
+			name:  "does not match a longer attribute name",
+			tag:   `<mxCell id="c1" xvalue="a" value='b'>`,
+			attr:  "value",
+			value: "c",
+			want:  `<mxCell id="c1" xvalue="a" value="c">`,
+		},
+		{
+			name:  "adds a missing attribute to a self-closing tag",
+			tag:   `<mxCell id="c1"/>`,
+			attr:  "style",
+			value: "html=1;",
+			want:  `<mxCell id="c1" style="html=1;"/>`,
+		},
+		{
+			name:  "escapes the value",
+			tag:   `<UserObject id="c1" label="">`,
+			attr:  "label",
+			value: `<b>"A" & B</b>`,
+			want:  `<UserObject id="c1" label="&lt;b&gt;&quot;A&quot; &amp; B&lt;/b&gt;">`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := setXMLAttr(tt.tag, tt.attr, tt.value); got != tt.want {
+				t.Errorf("setXMLAttr() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFixes(t *testing.T) {
+	const plain = `<mxfile><diagram name="A"><mxGraphModel><root>
+  <mxCell id="c1"   value="- nme: string" style="text;" vertex="1" parent="1"/>
+  <UserObject id="c2" label="User" package="app"><mxCell style="swimlane;" vertex="1" parent="1"/></UserObject>
+</root></mxGraphModel></diagram></mxfile>`
+
+	tests := []struct {
+		name    string
+		fixes   []Fix
+		applied int
+		want    []string // Các đoạn phải có trong kết quả
+	}{
+		{
+			name:    "composes two value fixes on one cell and keeps the formatting",
+			fixes:   []Fix{{Page: "A", CellID: "c1", Attribute: "value", Value: "- name: string"}, {Page: "A", CellID: "c1", Attribute: "value", Value: "- nme: String"}},
+			applied: 2,
+			want:    []string{`<mxCell id="c1"   value="- name: String" style="text;" vertex="1" parent="1"/>`},
+		},
+		{
+			name:    "writes the label and style of a UserObject where they live",
+			fixes:   []Fix{{Page: "A", CellID: "c2", Attribute: "value", Value: "Users"}, {Page: "A", CellID: "c2", Attribute: "style", Value: "swimlane;html=1;"}},
+			applied: 2,
+			want:    []string{`<UserObject id="c2" label="Users" package="app">`, `<mxCell style="swimlane;html=1;" vertex="1" parent="1"/>`},
+		},
+		{
+			name:    "ignores fixes for another page",
+			fixes:   []Fix{{Page: "B", CellID: "c1", Attribute: "value", Value: "- x: int"}},
+			applied: 0,
+			want:    []string{plain},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, applied, err := ApplyFixes([]byte(plain), tt.fixes)
+			if err != nil {
+				t.Fatalf("ApplyFixes failed: %v", err)
+			}
+			if applied != tt.applied {
+				t.Errorf("applied = %d, want %d", applied, tt.applied)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(string(out), w) {
+					t.Errorf("output does not contain %s:\n%s", w, out)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyFixesCompressedPage(t *testing.T) {
+	inner := `<mxGraphModel><root><mxCell id="c1" value="- nme: string" vertex="1"/></root></mxGraphModel>`
+	payload, err := CompressDiagram([]byte(inner))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := `<mxfile><diagram name="A">` + payload + `</diagram></mxfile>`
+
+	out, applied, err := ApplyFixes([]byte(data), []Fix{{Page: "A", CellID: "c1", Attribute: "value", Value: "- name: string"}})
+	if err != nil || applied != 1 {
+		t.Fatalf("ApplyFixes() = %d, %v; want 1 fix applied", applied, err)
+	}
+	body := strings.TrimSuffix(strings.TrimPrefix(string(out), `<mxfile><diagram name="A">`), `</diagram></mxfile>`)
+	fixed, err := DecompressDiagram(body)
+	if err != nil {
+		t.Fatalf("page is no longer compressed: %v", err)
+	}
+	if !strings.Contains(string(fixed), `value="- name: string"`) {
+		t.Errorf("decompressed page = %s, want the fixed value", fixed)
+	}
+}
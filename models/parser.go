@@ -4,23 +4,89 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io/ioutil"
+	"strings"
 )
 
-// ParseXML reads and parses the draw.io XML file.
-// ParseXML đọc và phân tích tệp XML draw.io.
-func ParseXML(inputFile string) ([]MxCell, error) {
+// ParseXML reads and parses the draw.io XML file, returning every page (tab) of the file.
+// Editable .drawio.svg and .drawio.png exports are accepted as well.
+// ParseXML đọc và phân tích tệp XML draw.io, trả về tất cả các trang (tab) của tệp.
+// Các tệp xuất .drawio.svg và .drawio.png có thể chỉnh sửa cũng được chấp nhận.
+func ParseXML(inputFile string) ([]Diagram, error) {
 	byteValue, err := ioutil.ReadFile(inputFile)
 	if err != nil {
 		return nil, fmt.Errorf("error reading file (lỗi đọc tệp): %v", err)
 	}
 
+	// Lấy mxfile được nhúng trong SVG/PNG (nếu có)
+	byteValue, err = ExtractMxFile(byteValue)
+	if err != nil {
+		return nil, fmt.Errorf("error reading embedded diagram (lỗi đọc biểu đồ nhúng): %v", err)
+	}
+
 	// MxFile là cấu trúc gốc của tệp XML draw.io, chứa tất cả dữ liệu cần thiết để trích xuất các cell.
 	var mxFile MxFile
 	if err := xml.Unmarshal(byteValue, &mxFile); err != nil {
 		return nil, fmt.Errorf("error parsing XML (lỗi phân tích XML): %v", err)
 	}
 
-	// Trả về danh sách các cell từ tệp XML đã phân tích. 
+	for i := range mxFile.Diagrams {
+		page := &mxFile.Diagrams[i]
+		if page.Name == "" {
+			page.Name = fmt.Sprintf("Page-%d", i+1)
+		}
+
+		// Biểu đồ nén: thân <diagram> là chuỗi base64 thay vì phần tử <mxGraphModel>
+		if len(page.MxGraphModel.Root.MxCells) == 0 && IsCompressedDiagram(page.Content) {
+			inner, err := DecompressDiagram(page.Content)
+			if err != nil {
+				return nil, fmt.Errorf("error decompressing page %q (lỗi giải nén trang): %v", page.Name, err)
+			}
+			if err := xml.Unmarshal(inner, &page.MxGraphModel); err != nil {
+				return nil, fmt.Errorf("error parsing compressed page %q (lỗi phân tích trang nén): %v", page.Name, err)
+			}
+		}
+
+		// Ghi lại tên trang cho từng cell để các bước sau có thể báo cáo vị trí
+		for j := range page.MxGraphModel.Root.MxCells {
+			page.MxGraphModel.Root.MxCells[j].Page = page.Name
+		}
+	}
+
+	// Trả về danh sách các trang từ tệp XML đã phân tích.
 	// Xem xml.go để biết cấu trúc của MxFile và cách nó tổ chức dữ liệu.
-	return mxFile.Diagram.MxGraphModel.Root.MxCells, nil
+	return mxFile.Diagrams, nil
+}
+
+// Cells returns the cells of the page.
+// Cells trả về các ô của trang.
+func (d Diagram) Cells() []MxCell {
+	return d.MxGraphModel.Root.MxCells
+}
+
+// SelectPages filters pages by name (case insensitive). An empty list selects every page.
+// SelectPages lọc các trang theo tên (không phân biệt hoa thường). Danh sách rỗng sẽ chọn tất cả các trang.
+func SelectPages(pages []Diagram, names []string) ([]Diagram, error) {
+	if len(names) == 0 {
+		return pages, nil
+	}
+
+	var selected []Diagram
+	for _, name := range names {
+		found := false
+		for _, page := range pages {
+			if strings.EqualFold(strings.TrimSpace(name), page.Name) {
+				selected = append(selected, page)
+				found = true
+				break
+			}
+		}
+		if !found {
+			var available []string
+			for _, page := range pages {
+				available = append(available, page.Name)
+			}
+			return nil, fmt.Errorf("page %q not found (không tìm thấy trang), available (có sẵn): %s", name, strings.Join(available, ", "))
+		}
+	}
+	return selected, nil
 }
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TypeRef represents a parsed Java type expression such as Map<String, List<? extends T>>[] or String...
+// TypeRef đại diện cho một biểu thức kiểu Java đã phân tích như Map<String, List<? extends T>>[] hoặc String...
+type TypeRef struct {
+	Name      string    // Simple or qualified name, "?" for a wildcard // Tên đơn hoặc đầy đủ, "?" với ký tự đại diện
+	Args      []TypeRef // Generic type arguments // Các đối số kiểu tổng quát
+	BoundKind string    // "extends" or "super" for a bounded wildcard // "extends" hoặc "super" với ký tự đại diện có giới hạn
+	Bound     *TypeRef  // Bound of the wildcard // Giới hạn của ký tự đại diện
+	Dims      int       // Number of array dimensions ([]) // Số chiều mảng ([])
+	Varargs   bool      // Declared with "..." // Được khai báo với "..."
+}
+
+// ParseType parses a Java type expression: qualified names, generics, wildcards, arrays and varargs.
+// ParseType phân tích một biểu thức kiểu Java: tên đầy đủ, kiểu tổng quát, ký tự đại diện, mảng và varargs.
+func ParseType(s string) (TypeRef, error) {
+	p := &typeParser{src: []rune(strings.TrimSpace(s))}
+	if len(p.src) == 0 {
+		return TypeRef{}, fmt.Errorf("empty type (kiểu rỗng)")
+	}
+	t, err := p.parseType()
+	if err != nil {
+		return TypeRef{}, fmt.Errorf("invalid type %q (kiểu không hợp lệ): %v", s, err)
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return TypeRef{}, fmt.Errorf("invalid type %q (kiểu không hợp lệ): unexpected %q", s, string(p.src[p.pos:]))
+	}
+	return t, nil
+}
+
+// String renders the type in Java syntax.
+// String hiển thị kiểu theo cú pháp Java.
+func (t TypeRef) String() string {
+	var sb strings.Builder
+	sb.WriteString(t.Name)
+	if t.Bound != nil {
+		sb.WriteString(" " + t.BoundKind + " " + t.Bound.String())
+	}
+	if t.Args != nil {
+		var args []string
+		for _, a := range t.Args {
+			args = append(args, a.String())
+		}
+		sb.WriteString("<" + strings.Join(args, ", ") + ">")
+	}
+	sb.WriteString(strings.Repeat("[]", t.Dims))
+	if t.Varargs {
+		sb.WriteString("...")
+	}
+	return sb.String()
+}
+
+// Names returns every type name referenced by the expression (outer type first).
+// Names trả về mọi tên kiểu được tham chiếu trong biểu thức (kiểu ngoài cùng trước).
+func (t TypeRef) Names() []string {
+	var names []string
+	if t.Name != "?" {
+		names = append(names, t.Name)
+	}
+	if t.Bound != nil {
+		names = append(names, t.Bound.Names()...)
+	}
+	for _, a := range t.Args {
+		names = append(names, a.Names()...)
+	}
+	return names
+}
+
+// typeParser is a small recursive-descent parser over a type expression.
+// typeParser là một bộ phân tích đệ quy xuống nhỏ cho biểu thức kiểu.
+type typeParser struct {
+	src []rune
+	pos int
+}
+
+func (p *typeParser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+// accept consumes tok (after optional spaces) and reports whether it was present.
+// accept tiêu thụ tok (sau các khoảng trắng tùy chọn) và cho biết nó có xuất hiện không.
+func (p *typeParser) accept(tok string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(string(p.src[p.pos:]), tok) {
+		p.pos += len([]rune(tok))
+		return true
+	}
+	return false
+}
+
+func (p *typeParser) ident() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		if r == '_' || r == '$' || unicode.IsLetter(r) || (p.pos > start && unicode.IsDigit(r)) {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return string(p.src[start:p.pos])
+}
+
+func (p *typeParser) parseType() (TypeRef, error) {
+	var t TypeRef
+	if p.accept("?") {
+		t.Name = "?"
+		// "? extends T" / "? super T"
+		save := p.pos
+		if kw := p.ident(); kw == "extends" || kw == "super" {
+			bound, err := p.parseType()
+			if err != nil {
+				return t, err
+			}
+			t.BoundKind, t.Bound = kw, &bound
+		} else {
+			p.pos = save
+		}
+		return t, nil
+	}
+
+	// Qualified name (java.util.List), then the generic arguments
+	// Tên đầy đủ (java.util.List), sau đó là các đối số kiểu tổng quát
+	for {
+		name := p.ident()
+		if name == "" {
+			return t, fmt.Errorf("expected a type name at %d", p.pos)
+		}
+		t.Name += name
+		if p.accept("<") {
+			t.Args = []TypeRef{}
+			if !p.accept(">") {
+				for {
+					arg, err := p.parseType()
+					if err != nil {
+						return t, err
+					}
+					t.Args = append(t.Args, arg)
+					if p.accept(",") {
+						continue
+					}
+					if !p.accept(">") {
+						return t, fmt.Errorf("expected '>' at %d", p.pos)
+					}
+					break
+				}
+			}
+			break
+		}
+		if !p.accept(".") {
+			break
+		}
+		if p.accept("..") {
+			t.Varargs = true
+			return t, nil
+		}
+		t.Name += "."
+	}
+
+	for p.accept("[") {
+		if !p.accept("]") {
+			return t, fmt.Errorf("expected ']' at %d", p.pos)
+		}
+		t.Dims++
+	}
+	if p.accept("...") {
+		t.Varargs = true
+	}
+	return t, nil
+}
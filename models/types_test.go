@@ -0,0 +1,52 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string   // Kiểu sau khi chuẩn hóa bởi String()
+		names   []string // Các tên kiểu được tham chiếu
+		wantErr bool
+	}{
+		{input: "int", want: "int", names: []string{"int"}},
+		{input: " java.util.List ", want: "java.util.List", names: []string{"java.util.List"}},
+		{input: "Map<String,List<Integer>>", want: "Map<String, List<Integer>>", names: []string{"Map", "String", "List", "Integer"}},
+		{input: "List<? extends Shape>", want: "List<? extends Shape>", names: []string{"List", "Shape"}},
+		{input: "Comparator< ? super T >", want: "Comparator<? super T>", names: []string{"Comparator", "T"}},
+		{input: "Class<?>", want: "Class<?>", names: []string{"Class"}},
+		{input: "List<>", want: "List<>", names: []string{"List"}},
+		{input: "int[][]", want: "int[][]", names: []string{"int"}},
+		{input: "String...", want: "String...", names: []string{"String"}},
+		{input: "List<T>[] ...", want: "List<T>[]...", names: []string{"List", "T"}},
+		{input: "", wantErr: true},
+		{input: "Map<String", wantErr: true},
+		{input: "int[", wantErr: true},
+		{input: "List<String> x", wantErr: true},
+		{input: "1abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseType(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseType(%q) = %s, want an error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseType(%q) failed: %v", tt.input, err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("ParseType(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+			if !reflect.DeepEqual(got.Names(), tt.names) {
+				t.Errorf("ParseType(%q).Names() = %v, want %v", tt.input, got.Names(), tt.names)
+			}
+		})
+	}
+}
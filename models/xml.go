@@ -1,18 +1,28 @@
 package models
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"regexp"
+)
+
+// rePlaceholder matches a %name% placeholder in the label of a wrapped cell.
+// rePlaceholder khớp một chỗ giữ %tên% trong nhãn của ô được bọc.
+var rePlaceholder = regexp.MustCompile(`%([^%\s]+)%`)
 
 // MxFile represents the root structure of a draw.io XML file.
 // MxFile đại diện cho cấu trúc gốc của tệp XML draw.io.
 type MxFile struct {
-	XMLName xml.Name `xml:"mxfile"`
-	Diagram Diagram  `xml:"diagram"`
+	XMLName  xml.Name  `xml:"mxfile"`
+	Diagrams []Diagram `xml:"diagram"` // One entry per page (tab) // Mỗi trang (tab) một phần tử
 }
 
-// Diagram represents the diagram node within the XML.
-// Diagram đại diện cho nút biểu đồ trong XML.
+// Diagram represents the diagram node (a single page) within the XML.
+// Diagram đại diện cho nút biểu đồ (một trang) trong XML.
 type Diagram struct {
+	ID           string       `xml:"id,attr"`   // Page id // ID của trang
+	Name         string       `xml:"name,attr"` // Page name shown on the tab // Tên trang hiển thị trên tab
 	MxGraphModel MxGraphModel `xml:"mxGraphModel"`
+	Content      string       `xml:",chardata"` // Compressed payload when saved with compression // Dữ liệu nén khi lưu với chế độ nén
 }
 
 // MxGraphModel represents the graph model containing the root element.
@@ -27,18 +37,98 @@ type Root struct {
 	MxCells []MxCell `xml:"mxCell"`
 }
 
+// UnmarshalXML collects mxCell children in document order and unwraps cells wrapped in
+// <UserObject> or <object> elements (added by draw.io for custom properties and links).
+// UnmarshalXML thu thập các phần tử mxCell con theo thứ tự tài liệu và mở các ô được bọc trong
+// <UserObject> hoặc <object> (draw.io thêm vào khi có thuộc tính tùy chỉnh hoặc liên kết).
+func (r *Root) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "mxCell":
+				var cell MxCell
+				if err := d.DecodeElement(&cell, &t); err != nil {
+					return err
+				}
+				r.MxCells = append(r.MxCells, cell)
+			case "UserObject", "object":
+				var wrapper UserObject
+				if err := d.DecodeElement(&wrapper, &t); err != nil {
+					return err
+				}
+				r.MxCells = append(r.MxCells, wrapper.Unwrap())
+			default:
+				if err := d.Skip(); err != nil {
+					return err
+				}
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// UserObject represents a <UserObject> or <object> wrapper holding a label, custom properties and the actual mxCell.
+// UserObject đại diện cho phần tử bọc <UserObject> hoặc <object> chứa nhãn, các thuộc tính tùy chỉnh và mxCell thực sự.
+type UserObject struct {
+	Attrs []xml.Attr `xml:",any,attr"` // id, label and custom properties // id, nhãn và các thuộc tính tùy chỉnh
+	Cell  MxCell     `xml:"mxCell"`
+}
+
+// Unwrap converts the wrapper into a plain MxCell: the wrapper id becomes the cell id,
+// label becomes the value and every other attribute is kept in Properties.
+// Unwrap chuyển phần tử bọc thành MxCell thông thường: id của phần tử bọc trở thành id của ô,
+// label trở thành giá trị và mọi thuộc tính khác được giữ trong Properties.
+func (uo UserObject) Unwrap() MxCell {
+	cell := uo.Cell
+	cell.Properties = make(map[string]string)
+	for _, attr := range uo.Attrs {
+		switch attr.Name.Local {
+		case "id":
+			cell.ID = attr.Value
+		case "label":
+			cell.Value = attr.Value
+		default:
+			cell.Properties[attr.Name.Local] = attr.Value
+		}
+	}
+
+	// placeholders="1": thay %tên% trong nhãn bằng giá trị thuộc tính tương ứng
+	if cell.Properties["placeholders"] == "1" {
+		// Thay trong một lượt: giá trị được chèn vào không bị thay lại, chỗ giữ không rõ được giữ nguyên
+		cell.Value = rePlaceholder.ReplaceAllStringFunc(cell.Value, func(match string) string {
+			if val, ok := cell.Properties[match[1:len(match)-1]]; ok {
+				return val
+			}
+			return match
+		})
+	}
+	return cell
+}
+
 // MxCell represents a single element in the diagram (vertex or edge).
 // MxCell đại diện cho một phần tử đơn lẻ trong biểu đồ (đỉnh hoặc cạnh).
 type MxCell struct {
-	ID       string     `xml:"id,attr"`
-	Parent   string     `xml:"parent,attr"`
-	Value    string     `xml:"value,attr"`
-	Style    string     `xml:"style,attr"`
-	Vertex   string     `xml:"vertex,attr"`
-	Edge     string     `xml:"edge,attr"`
-	Source   string     `xml:"source,attr"`
-	Target   string     `xml:"target,attr"`
-	Geometry MxGeometry `xml:"mxGeometry"`
+	ID          string     `xml:"id,attr"`
+	Parent      string     `xml:"parent,attr"`
+	Value       string     `xml:"value,attr"`
+	Style       string     `xml:"style,attr"`
+	Vertex      string     `xml:"vertex,attr"`
+	Edge        string     `xml:"edge,attr"`
+	Source      string     `xml:"source,attr"`
+	Target      string     `xml:"target,attr"`
+	Connectable string     `xml:"connectable,attr"`
+	Geometry    MxGeometry `xml:"mxGeometry"`
+	Page        string     `xml:"-"` // Name of the page holding the cell (set by ParseXML) // Tên trang chứa ô (do ParseXML gán)
+
+	// Properties holds custom attributes from a <UserObject>/<object> wrapper (e.g. package, table, annotations).
+	// Properties chứa các thuộc tính tùy chỉnh từ phần tử bọc <UserObject>/<object> (ví dụ package, table, annotations).
+	Properties map[string]string `xml:"-"`
 }
 
 // MxGeometry represents the geometric properties of a cell.
@@ -48,19 +138,21 @@ type MxGeometry struct {
 	Y      string `xml:"y,attr"`
 	Width  string `xml:"width,attr"`
 	Height string `xml:"height,attr"`
+	// Relative is "1" for edge labels, whose X runs from -1 (source end) to 1 (target end).
+	// Relative là "1" với nhãn của cạnh, khi đó X chạy từ -1 (đầu nguồn) đến 1 (đầu đích).
+	Relative string `xml:"relative,attr"`
 }
 
-
 /* Nesting of structs allows us to easily navigate the XML structure and extract the necessary information for analysis and code generation.
 * Việc lồng các struct cho phép chúng ta dễ dàng điều hướng cấu trúc XML và trích xuất thông tin cần thiết cho việc phân tích và tạo mã.
 * Kiến trúc:
 * MxFile
 *  └── XMLName
-*  └── Diagram
+*  └── []Diagram (one per page / mỗi trang một phần tử)
 *       └── MxGraphModel
 *            └── Root
-*                 └── []MxCell
+*                 └── []MxCell (UserObject/object wrappers are unwrapped / các phần tử bọc được mở ra)
 *  						  └──MxGeometry
-* 
+*
 * Each MxCell can represent a class, attribute, method, or relationship depending on its style and properties
-*/
\ No newline at end of file
+ */
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestUserObjectUnwrapPlaceholders(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs map[string]string
+		want  string
+	}{
+		{
+			name:  "substitutes properties",
+			attrs: map[string]string{"placeholders": "1", "label": "%name%: %type%", "name": "id", "type": "long"},
+			want:  "id: long",
+		},
+		{
+			name:  "keeps unknown placeholders",
+			attrs: map[string]string{"placeholders": "1", "label": "%name% %missing%", "name": "id"},
+			want:  "id %missing%",
+		},
+		{
+			name:  "does not substitute inserted values again",
+			attrs: map[string]string{"placeholders": "1", "label": "%a%", "a": "%b%", "b": "x"},
+			want:  "%b%",
+		},
+		{
+			name:  "ignores placeholders when disabled",
+			attrs: map[string]string{"label": "%name%", "name": "id"},
+			want:  "%name%",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uo := UserObject{Attrs: []xml.Attr{{Name: xml.Name{Local: "id"}, Value: "c1"}}}
+			for key, val := range tt.attrs {
+				uo.Attrs = append(uo.Attrs, xml.Attr{Name: xml.Name{Local: key}, Value: val})
+			}
+			cell := uo.Unwrap()
+			if cell.ID != "c1" || cell.Value != tt.want {
+				t.Errorf("Unwrap() = %s %q, want c1 %q", cell.ID, cell.Value, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package output
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveSinkReproducible(t *testing.T) {
+	tests := []struct {
+		name string
+		open func(path string) (*ArchiveSink, error)
+	}{
+		{name: "zip", open: NewZipSink},
+		{name: "tar", open: func(path string) (*ArchiveSink, error) { return NewTarSink(path, false) }},
+		{name: "tar.gz", open: func(path string) (*ArchiveSink, error) { return NewTarSink(path, true) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var archives [][]byte
+			for i := 0; i < 2; i++ {
+				path := filepath.Join(t.TempDir(), "out."+tt.name)
+				sink, err := tt.open(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sink.WriteFile("User.java", []byte("public class User {}\n"))
+				if err := sink.Close(); err != nil {
+					t.Fatal(err)
+				}
+				data, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				archives = append(archives, data)
+			}
+			if !bytes.Equal(archives[0], archives[1]) {
+				t.Errorf("two archives of the same files differ")
+			}
+			if modTime := entryModTime(t, tt.name, archives[0]); !modTime.Equal(archiveModTime) {
+				t.Errorf("entry time = %v, want %v", modTime, archiveModTime)
+			}
+		})
+	}
+}
+
+// entryModTime returns the modification time of the first entry of an archive.
+// entryModTime trả về thời gian sửa đổi của mục đầu tiên trong một tệp nén.
+func entryModTime(t *testing.T, kind string, data []byte) time.Time {
+	if kind == "zip" {
+		zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return zr.File[0].Modified
+	}
+	var r io.Reader = bytes.NewReader(data)
+	if kind == "tar.gz" {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gz
+	}
+	header, err := tar.NewReader(r).Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return header.ModTime
+}
@@ -0,0 +1,91 @@
+package output
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// FileSink implements Sink on the real filesystem, with paths relative to a root folder.
+// FileSink triển khai Sink trên hệ thống tệp thật, với đường dẫn tương đối với một thư mục gốc.
+type FileSink struct {
+	root string // "" là thư mục làm việc
+}
+
+// NewFileSink creates a FileSink writing under root ("" for the working directory).
+// NewFileSink tạo một FileSink ghi vào bên dưới root ("" cho thư mục làm việc).
+func NewFileSink(root string) *FileSink {
+	return &FileSink{root: root}
+}
+
+// resolve returns the location of path on disk.
+// resolve trả về vị trí của path trên đĩa.
+func (fs *FileSink) resolve(path string) string {
+	if fs.root == "" {
+		return path
+	}
+	return filepath.Join(fs.root, path)
+}
+
+// Exists reports whether a file is present on disk.
+// Exists cho biết một tệp có trên đĩa hay không.
+func (fs *FileSink) Exists(path string) bool {
+	_, err := os.Stat(fs.resolve(path))
+	return err == nil
+}
+
+// ReadFile reads a file from disk.
+// ReadFile đọc một tệp từ đĩa.
+func (fs *FileSink) ReadFile(path string) ([]byte, error) {
+	return ioutil.ReadFile(fs.resolve(path))
+}
+
+// WriteFile writes a file to disk atomically: the content goes to a temporary file in the same folder
+// which is then renamed into place, so a failed write never leaves a half-written file behind.
+// The package folders (com/acme/billing/) are created if needed.
+// WriteFile ghi một tệp ra đĩa một cách nguyên tử: nội dung được ghi vào một tệp tạm trong cùng thư mục
+// rồi được đổi tên vào đúng vị trí, nên một lần ghi thất bại không bao giờ để lại tệp ghi dở.
+// Các thư mục của gói (com/acme/billing/) được tạo nếu cần.
+func (fs *FileSink) WriteFile(path string, data []byte) error {
+	path = fs.resolve(path)
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("error creating folder (lỗi tạo thư mục) %s: %v", dir, err)
+	}
+
+	// Tệp tạm phải nằm cùng hệ thống tệp để os.Rename là nguyên tử
+	tmp, err := ioutil.TempFile(dir, "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error creating temporary file (lỗi tạo tệp tạm) for %s: %v", path, err)
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// TempFile tạo tệp với quyền 0600; tệp bị thay thế giữ nguyên quyền của nó
+		mode := os.FileMode(0644)
+		if info, statErr := os.Stat(path); statErr == nil {
+			mode = info.Mode().Perm()
+		}
+		err = os.Chmod(tmp.Name(), mode)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("error writing file (lỗi ghi tệp) %s: %v", path, err)
+	}
+	return nil
+}
+
+// Close has nothing to finish on the filesystem.
+// Close không có gì cần hoàn tất trên hệ thống tệp.
+func (fs *FileSink) Close() error {
+	return nil
+}
@@ -0,0 +1,64 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkWriteFile(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing os.FileMode // 0 nghĩa là tệp chưa tồn tại
+		wantMode os.FileMode
+	}{
+		{name: "new file", wantMode: 0644},
+		{name: "keeps the mode of a replaced file", existing: 0600, wantMode: 0600},
+		{name: "keeps an executable mode", existing: 0755, wantMode: 0755},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "com", "acme", "User.java")
+			if tt.existing != 0 {
+				os.MkdirAll(filepath.Dir(path), 0755)
+				if err := os.WriteFile(path, []byte("old"), tt.existing); err != nil {
+					t.Fatal(err)
+				}
+				os.Chmod(path, tt.existing)
+			}
+
+			sink := NewFileSink(dir)
+			if err := sink.WriteFile(filepath.Join("com", "acme", "User.java"), []byte("new")); err != nil {
+				t.Fatalf("WriteFile failed: %v", err)
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != tt.wantMode {
+				t.Errorf("mode = %v, want %v", info.Mode().Perm(), tt.wantMode)
+			}
+			if data, _ := os.ReadFile(path); string(data) != "new" {
+				t.Errorf("content = %q, want new", data)
+			}
+			// Không còn tệp tạm nào sau khi đổi tên
+			entries, _ := os.ReadDir(filepath.Dir(path))
+			if len(entries) != 1 {
+				t.Errorf("folder holds %d entries, want only User.java", len(entries))
+			}
+		})
+	}
+}
+
+func TestFileSinkWriteFileFailure(t *testing.T) {
+	dir := t.TempDir()
+	// Một tệp chắn chỗ thư mục của gói: không thể tạo com/User.java
+	if err := os.WriteFile(filepath.Join(dir, "com"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := NewFileSink(dir).WriteFile(filepath.Join("com", "User.java"), []byte("new")); err == nil {
+		t.Errorf("WriteFile succeeded under a file")
+	}
+}
@@ -0,0 +1,18 @@
+package output
+
+// Sink defines where generated files are written: the filesystem, memory, stdout or an archive.
+// Sink định nghĩa nơi các tệp được tạo được ghi vào: hệ thống tệp, bộ nhớ, stdout hoặc một tệp nén.
+type Sink interface {
+	// Exists reports whether the destination already holds a file at path.
+	// Exists cho biết đích đã chứa một tệp tại path hay chưa.
+	Exists(path string) bool
+	// ReadFile returns the current content of a file in the destination (used by merge mode).
+	// ReadFile trả về nội dung hiện tại của một tệp ở đích (được dùng bởi chế độ hợp nhất).
+	ReadFile(path string) ([]byte, error)
+	// WriteFile stores a file, creating parent folders as needed.
+	// WriteFile lưu một tệp, tạo các thư mục cha nếu cần.
+	WriteFile(path string, data []byte) error
+	// Close finishes the output (archives are completed here).
+	// Close hoàn tất đầu ra (các tệp nén được hoàn thiện tại đây).
+	Close() error
+}
@@ -0,0 +1,70 @@
+package output
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// MemorySink implements Sink with a map, for previews and for running the generation without a disk.
+// MemorySink triển khai Sink bằng một map, dùng để xem trước và chạy quá trình tạo mà không cần đĩa.
+type MemorySink struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+// NewMemorySink creates an empty MemorySink.
+// NewMemorySink tạo một MemorySink rỗng.
+func NewMemorySink() *MemorySink {
+	return &MemorySink{files: make(map[string][]byte)}
+}
+
+// Exists reports whether a file was written (or seeded) at path.
+// Exists cho biết một tệp đã được ghi (hoặc nạp sẵn) tại path hay chưa.
+func (ms *MemorySink) Exists(path string) bool {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	_, ok := ms.files[filepath.ToSlash(path)]
+	return ok
+}
+
+// ReadFile returns the content stored at path, or os.ErrNotExist.
+// ReadFile trả về nội dung được lưu tại path, hoặc os.ErrNotExist.
+func (ms *MemorySink) ReadFile(path string) ([]byte, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	data, ok := ms.files[filepath.ToSlash(path)]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// WriteFile stores a copy of data at path.
+// WriteFile lưu một bản sao của data tại path.
+func (ms *MemorySink) WriteFile(path string, data []byte) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.files[filepath.ToSlash(path)] = append([]byte(nil), data...)
+	return nil
+}
+
+// Close has nothing to finish in memory.
+// Close không có gì cần hoàn tất trong bộ nhớ.
+func (ms *MemorySink) Close() error {
+	return nil
+}
+
+// Paths returns the stored paths in sorted order.
+// Paths trả về các đường dẫn đã lưu theo thứ tự đã sắp xếp.
+func (ms *MemorySink) Paths() []string {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	paths := make([]string, 0, len(ms.files))
+	for path := range ms.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
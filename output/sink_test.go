@@ -0,0 +1,180 @@
+package output
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// readTar returns the entries of a tar archive, gunzipped first when compressed is true.
+// readTar trả về các mục của một tệp nén tar, giải nén gzip trước khi compressed là true.
+func readTar(t *testing.T, path string, compressed bool) map[string]string {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if compressed {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r = gz
+	}
+	entries := make(map[string]string)
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		entries[header.Name] = string(data)
+	}
+}
+
+// readZip returns the entries of a zip archive.
+// readZip trả về các mục của một tệp nén zip.
+func readZip(t *testing.T, path string) map[string]string {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer zr.Close()
+	entries := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		entries[f.Name] = string(data)
+	}
+	return entries
+}
+
+func TestSinks(t *testing.T) {
+	files := map[string]string{
+		"com/acme/User.java": "public class User {}\n",
+		"Report.md":          "# Report\n",
+	}
+
+	tests := []struct {
+		name     string
+		open     func(dir string) (Sink, error)
+		readable bool                                             // Tệp đã ghi có thể đọc lại qua sink
+		contents func(t *testing.T, dir string) map[string]string // Nội dung thực sự được ghi ra, sau Close
+	}{
+		{
+			name:     "memory",
+			open:     func(dir string) (Sink, error) { return NewMemorySink(), nil },
+			readable: true,
+		},
+		{
+			name:     "file",
+			open:     func(dir string) (Sink, error) { return NewFileSink(filepath.Join(dir, "out")), nil },
+			readable: true,
+			contents: func(t *testing.T, dir string) map[string]string {
+				entries := make(map[string]string)
+				for path := range files {
+					data, err := os.ReadFile(filepath.Join(dir, "out", path))
+					if err != nil {
+						t.Fatal(err)
+					}
+					entries[path] = string(data)
+				}
+				return entries
+			},
+		},
+		{
+			name:     "zip",
+			open:     func(dir string) (Sink, error) { return NewZipSink(filepath.Join(dir, "out.zip")) },
+			contents: func(t *testing.T, dir string) map[string]string { return readZip(t, filepath.Join(dir, "out.zip")) },
+		},
+		{
+			name: "tar",
+			open: func(dir string) (Sink, error) { return NewTarSink(filepath.Join(dir, "out.tar"), false) },
+			contents: func(t *testing.T, dir string) map[string]string {
+				return readTar(t, filepath.Join(dir, "out.tar"), false)
+			},
+		},
+		{
+			name: "tar.gz",
+			open: func(dir string) (Sink, error) { return NewTarSink(filepath.Join(dir, "out.tar.gz"), true) },
+			contents: func(t *testing.T, dir string) map[string]string {
+				return readTar(t, filepath.Join(dir, "out.tar.gz"), true)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			sink, err := tt.open(dir)
+			if err != nil {
+				t.Fatalf("open failed: %v", err)
+			}
+			if sink.Exists("Report.md") {
+				t.Errorf("Exists(Report.md) before writing = true")
+			}
+			for path, content := range files {
+				if err := sink.WriteFile(filepath.FromSlash(path), []byte(content)); err != nil {
+					t.Fatalf("WriteFile(%s) failed: %v", path, err)
+				}
+			}
+			if tt.readable {
+				for path, content := range files {
+					data, err := sink.ReadFile(path)
+					if !sink.Exists(path) || err != nil || string(data) != content {
+						t.Errorf("ReadFile(%s) = %q, %v; want %q", path, data, err, content)
+					}
+				}
+			} else if _, err := sink.ReadFile("Report.md"); !os.IsNotExist(err) {
+				t.Errorf("ReadFile on a write-only sink = %v, want os.ErrNotExist", err)
+			}
+			if err := sink.Close(); err != nil {
+				t.Fatalf("Close failed: %v", err)
+			}
+			if tt.contents != nil {
+				if got := tt.contents(t, dir); !reflect.DeepEqual(got, files) {
+					t.Errorf("written files = %q, want %q", got, files)
+				}
+			}
+		})
+	}
+}
+
+func TestMemorySinkPaths(t *testing.T) {
+	sink := NewMemorySink()
+	for _, path := range []string{"b/B.java", "A.java", filepath.Join("a", "C.java")} {
+		sink.WriteFile(path, nil)
+	}
+	if got, want := sink.Paths(), []string{"A.java", "a/C.java", "b/B.java"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Paths() = %v, want %v", got, want)
+	}
+}
+
+func TestStdoutSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewStdoutSink(&buf)
+	sink.WriteFile(filepath.Join("com", "User.java"), []byte("class User {}"))
+	sink.WriteFile("Report.md", []byte("# Report\n"))
+	want := "// ===== com/User.java =====\nclass User {}\n// ===== Report.md =====\n# Report\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+	if sink.Exists("Report.md") {
+		t.Errorf("Exists(Report.md) = true on stdout")
+	}
+}
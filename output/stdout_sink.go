@@ -0,0 +1,57 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// StdoutSink implements Sink by concatenating every file to a writer (stdout), each one after a banner
+// with its path. Nothing exists beforehand, so every file is reported as created.
+// StdoutSink triển khai Sink bằng cách nối mọi tệp vào một writer (stdout), mỗi tệp sau một dòng tiêu đề
+// chứa đường dẫn của nó. Không có gì tồn tại từ trước, nên mọi tệp được báo là tạo mới.
+type StdoutSink struct {
+	writer io.Writer
+}
+
+// NewStdoutSink creates a StdoutSink writing to w (os.Stdout when nil).
+// NewStdoutSink tạo một StdoutSink ghi vào w (os.Stdout khi nil).
+func NewStdoutSink(w io.Writer) *StdoutSink {
+	if w == nil {
+		w = os.Stdout
+	}
+	return &StdoutSink{writer: w}
+}
+
+// Exists always returns false: the output starts empty.
+// Exists luôn trả về false: đầu ra bắt đầu rỗng.
+func (ss *StdoutSink) Exists(path string) bool {
+	return false
+}
+
+// ReadFile always fails with os.ErrNotExist.
+// ReadFile luôn thất bại với os.ErrNotExist.
+func (ss *StdoutSink) ReadFile(path string) ([]byte, error) {
+	return nil, &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+}
+
+// WriteFile prints "// ===== path =====" followed by the content.
+// WriteFile in "// ===== path =====" theo sau là nội dung.
+func (ss *StdoutSink) WriteFile(path string, data []byte) error {
+	content := string(data)
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if _, err := fmt.Fprintf(ss.writer, "// ===== %s =====\n%s", filepath.ToSlash(path), content); err != nil {
+		return fmt.Errorf("error writing to stdout (lỗi ghi ra stdout): %v", err)
+	}
+	return nil
+}
+
+// Close has nothing to finish on stdout.
+// Close không có gì cần hoàn tất trên stdout.
+func (ss *StdoutSink) Close() error {
+	return nil
+}
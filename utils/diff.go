@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffLine is one line of an edit script: ' ' kept, '-' removed, '+' added.
+// diffLine là một dòng của kịch bản chỉnh sửa: ' ' giữ, '-' xóa, '+' thêm.
+type diffLine struct {
+	op      byte
+	text    string
+	oldLine int // Số dòng (từ 0) trong văn bản cũ, trước dòng này với '+'
+	newLine int // Số dòng (từ 0) trong văn bản mới, trước dòng này với '-'
+}
+
+// UnifiedDiff returns the differences between two texts in unified diff format with the given number
+// of context lines, or "" when they are equal. oldName and newName are written in the --- and +++ headers.
+// UnifiedDiff trả về sự khác biệt giữa hai văn bản theo định dạng unified diff với số dòng ngữ cảnh
+// đã cho, hoặc "" khi chúng bằng nhau. oldName và newName được ghi trong tiêu đề --- và +++.
+func UnifiedDiff(oldName, newName, oldText, newText string, context int) string {
+	if oldText == newText {
+		return ""
+	}
+	script := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	sb.WriteString("--- " + oldName + "\n")
+	sb.WriteString("+++ " + newName + "\n")
+
+	// Gom các thay đổi gần nhau (cách nhau không quá 2*context dòng) vào cùng một hunk
+	for i := 0; i < len(script); {
+		if script[i].op == ' ' {
+			i++
+			continue
+		}
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end := i
+		for j := i; j < len(script); j++ {
+			if script[j].op != ' ' {
+				end = j
+			} else if j-end > 2*context {
+				break
+			}
+		}
+		stop := end + context + 1
+		if stop > len(script) {
+			stop = len(script)
+		}
+		writeHunk(&sb, script[start:stop])
+		i = stop
+	}
+	return sb.String()
+}
+
+// writeHunk writes the @@ header and the lines of one hunk.
+// writeHunk ghi tiêu đề @@ và các dòng của một hunk.
+func writeHunk(sb *strings.Builder, lines []diffLine) {
+	oldCount, newCount := 0, 0
+	for _, l := range lines {
+		if l.op != '+' {
+			oldCount++
+		}
+		if l.op != '-' {
+			newCount++
+		}
+	}
+	// Với phạm vi rỗng, unified diff ghi số dòng đứng trước nó
+	oldStart, newStart := lines[0].oldLine, lines[0].newLine
+	if oldCount > 0 {
+		oldStart++
+	}
+	if newCount > 0 {
+		newStart++
+	}
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount)
+
+	for _, l := range lines {
+		sb.WriteByte(l.op)
+		sb.WriteString(l.text)
+		if !strings.HasSuffix(l.text, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// diffLines computes an edit script turning a into b from their longest common subsequence.
+// diffLines tính kịch bản chỉnh sửa biến a thành b từ dãy con chung dài nhất của chúng.
+func diffLines(a, b []string) []diffLine {
+	// lcs[i][j] là độ dài dãy con chung dài nhất của a[i:] và b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var script []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			script = append(script, diffLine{' ', a[i], i, j})
+			i, j = i+1, j+1
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			script = append(script, diffLine{'-', a[i], i, j})
+			i++
+		default:
+			script = append(script, diffLine{'+', b[j], i, j})
+			j++
+		}
+	}
+	return script
+}
+
+// splitLines splits a text into lines, each keeping its newline.
+// splitLines tách văn bản thành các dòng, mỗi dòng giữ ký tự xuống dòng của nó.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,71 @@
+package utils
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		old     string
+		new     string
+		context int
+		want    string
+	}{
+		{
+			name: "equal texts",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name:    "changed line with context",
+			old:     "a\nb\nc\nd\ne\n",
+			new:     "a\nb\nX\nd\ne\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -2,3 +2,3 @@\n b\n-c\n+X\n d\n",
+		},
+		{
+			name:    "added lines to an empty file",
+			old:     "",
+			new:     "a\nb\n",
+			context: 3,
+			want:    "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "removed lines",
+			old:     "a\nb\nc\n",
+			new:     "a\nc\n",
+			context: 0,
+			want:    "--- old\n+++ new\n@@ -2,1 +1,0 @@\n-b\n",
+		},
+		{
+			name:    "distant changes give two hunks",
+			old:     "1\n2\n3\n4\n5\n6\n7\n8\n",
+			new:     "X\n2\n3\n4\n5\n6\n7\nY\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -1,2 +1,2 @@\n-1\n+X\n 2\n@@ -7,2 +7,2 @@\n 7\n-8\n+Y\n",
+		},
+		{
+			name:    "near changes share a hunk",
+			old:     "1\n2\n3\n4\n",
+			new:     "X\n2\n3\nY\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+X\n 2\n 3\n-4\n+Y\n",
+		},
+		{
+			name:    "missing newline at end of file",
+			old:     "a\nb",
+			new:     "a\nb\n",
+			context: 1,
+			want:    "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnifiedDiff("old", "new", tt.old, tt.new, tt.context)
+			if got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
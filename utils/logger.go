@@ -2,12 +2,17 @@ package utils
 
 import (
 	"fmt"
+	"os"
 )
 
 // Global Log variables
 // Các biến Log toàn cục
 var VerboseMode bool
 
+// StderrMode sends log messages to stderr, keeping stdout for machine readable output (JSON, SARIF).
+// StderrMode gửi các tin nhắn log ra stderr, giữ stdout cho đầu ra máy đọc được (JSON, SARIF).
+var StderrMode bool
+
 // Colors for console output
 // Các màu cho đầu ra console
 const (
@@ -27,9 +32,13 @@ func SetupLogging() {
 	// No-op
 }
 
-// LogInfo prints a message to stdout.
-// LogInfo in một tin nhắn ra stdout.
+// LogInfo prints a message to stdout (stderr in StderrMode).
+// LogInfo in một tin nhắn ra stdout (stderr trong StderrMode).
 func LogInfo(msg string) {
+	if StderrMode {
+		fmt.Fprintln(os.Stderr, msg)
+		return
+	}
 	fmt.Println(msg)
 }
 
@@ -37,7 +46,7 @@ func LogInfo(msg string) {
 // LogVerbose in một tin nhắn nếu VerboseMode là đúng.
 func LogVerbose(msg string) {
 	if VerboseMode {
-		fmt.Println(Purple + "[VERBOSE] " + Cyan + msg + Reset)
+		LogInfo(Purple + "[VERBOSE] " + Cyan + msg + Reset)
 	}
 }
 
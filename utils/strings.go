@@ -20,7 +20,9 @@ func CleanHTML(s string) string {
 	// Regex: Matches specific HTML tags (case insensitive)
 	// Regex: Khớp các thẻ HTML cụ thể (không phân biệt hoa thường)
 	// p, div, span, i, b, em, strong, font
-	re := regexp.MustCompile(`(?i)</?(div|p|span|i|b|em|strong|font)[^>]*>`)
+	// The tag name must end at a space, "/" or ">" so generics like <Integer> or <Boolean> are kept
+	// Tên thẻ phải kết thúc bằng khoảng trắng, "/" hoặc ">" để giữ lại các generic như <Integer> hoặc <Boolean>
+	re := regexp.MustCompile(`(?i)</?(div|p|span|i|b|em|strong|font)(\s[^>]*)?/?>`)
 	clean := re.ReplaceAllString(s, "")
 
 	// 2. Decode HTML entities (properly handles &lt; &gt; &nbsp; etc)
@@ -83,3 +85,36 @@ var reValidIdentifier = regexp.MustCompile(`[^a-zA-Z0-9_$]`)
 func SanitizeName(name string) string {
 	return reValidIdentifier.ReplaceAllString(name, "")
 }
+
+// SplitTopLevel splits a string on sep, ignoring separators nested inside brackets or quotes.
+// SplitTopLevel tách chuỗi theo sep, bỏ qua các dấu phân cách nằm trong ngoặc hoặc dấu nháy.
+func SplitTopLevel(s string, sep rune) []string {
+	var parts []string
+	var current strings.Builder
+	depth := 0
+	var quote rune
+
+	for _, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(' || r == '<' || r == '[' || r == '{':
+			depth++
+		case r == ')' || r == '>' || r == ']' || r == '}':
+			if depth > 0 {
+				depth--
+			}
+		case r == sep && depth == 0:
+			parts = append(parts, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(r)
+	}
+	parts = append(parts, current.String())
+	return parts
+}